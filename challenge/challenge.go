@@ -0,0 +1,167 @@
+package challenge
+
+import (
+	"context"
+	"cryptopals/score"
+	"cryptopals/util"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Env is everything the solvers get from the command line.
+type Env struct {
+	// Context is cancelled when the challenge times out. Solvers with long loops should check it.
+	Context     context.Context
+	Random      util.Rand
+	Model       score.Scorer
+	ColumnModel score.Scorer
+}
+
+type Challenge struct {
+	Set    int
+	Number int
+	// Solve returns a typed result of the challenge, which also prints itself. An attack that
+	// didn't work is an error, so that the run fails.
+	Solve func(env *Env) (fmt.Stringer, error)
+}
+
+var registry = make(map[int]Challenge)
+
+// Register is meant to be called from the init functions of the set packages.
+func Register(set int, number int, solve func(env *Env) (fmt.Stringer, error)) {
+	if _, ok := registry[number]; ok {
+		panic(fmt.Sprintf("challenge %d is registered twice", number))
+	}
+	registry[number] = Challenge{Set: set, Number: number, Solve: solve}
+}
+
+// All returns the registered challenges ordered by number.
+func All() []Challenge {
+	res := make([]Challenge, 0, len(registry))
+	for _, c := range registry {
+		res = append(res, c)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Number < res[j].Number
+	})
+	return res
+}
+
+// Select parses a challenge number like "12", a set like "set2", or "all".
+func Select(spec string) ([]Challenge, error) {
+	if spec == "all" {
+		return All(), nil
+	}
+	if rest, ok := strings.CutPrefix(spec, "set"); ok {
+		set, err := strconv.Atoi(rest)
+		if err != nil {
+			return nil, fmt.Errorf("bad set %q", spec)
+		}
+		var res []Challenge
+		for _, c := range All() {
+			if c.Set == set {
+				res = append(res, c)
+			}
+		}
+		if len(res) == 0 {
+			return nil, fmt.Errorf("no challenges in set %d", set)
+		}
+		return res, nil
+	}
+	number, err := strconv.Atoi(spec)
+	if err != nil {
+		return nil, fmt.Errorf("expected a challenge number, a set or \"all\", got %q", spec)
+	}
+	c, ok := registry[number]
+	if !ok {
+		return nil, fmt.Errorf("challenge %d is not implemented", number)
+	}
+	return []Challenge{c}, nil
+}
+
+type Result struct {
+	Set    int `json:"set"`
+	Number int `json:"challenge"`
+	// Seed replays the run with -seed. It is zero for runs on crypto/rand, which can't be replayed.
+	Seed     int64         `json:"seed,omitempty"`
+	Answer   fmt.Stringer  `json:"answer,omitempty"`
+	Output   string        `json:"output"`
+	Duration time.Duration `json:"duration_ns"`
+	Err      error         `json:"-"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// ErrTimeout means that the solver ran out of time. Its context is cancelled, so a solver that
+// checks it stops soon; one that doesn't keeps running in the background, but only with its own Env.
+var ErrTimeout = errors.New("timed out")
+
+// NewEnv gives every challenge its own random source derived from the seed of the run, so that
+// a challenge gets the same randomness whether it runs alone or as part of a set, and solvers
+// never share a source. A zero seed means crypto/rand.
+func NewEnv(seed int64, number int, model score.Scorer, columnModel score.Scorer) Env {
+	env := Env{Context: context.Background(), Random: util.CryptoRand, Model: model, ColumnModel: columnModel}
+	if seed != 0 {
+		env.Random = util.NewSeededRand(int64(uint64(seed) ^ uint64(number)*0x9e3779b97f4a7c15))
+	}
+	return env
+}
+
+type solved struct {
+	answer fmt.Stringer
+	output string
+	err    error
+}
+
+// Run solves the challenge. A panic in the solver, or a nil answer, is reported as an error.
+func Run(c Challenge, env Env, timeout time.Duration) Result {
+	res := Result{Set: c.Set, Number: c.Number}
+	if env.Context == nil {
+		env.Context = context.Background()
+	}
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		env.Context, cancel = context.WithTimeout(env.Context, timeout)
+	} else {
+		env.Context, cancel = context.WithCancel(env.Context)
+	}
+	defer cancel()
+
+	done := make(chan solved, 1)
+	start := time.Now()
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- solved{err: fmt.Errorf("panic: %v", r)}
+			}
+		}()
+		answer, err := c.Solve(&env)
+		if err == nil && answer == nil {
+			err = fmt.Errorf("the solver returned no answer")
+		}
+		if err != nil {
+			done <- solved{err: err}
+			return
+		}
+		// String runs here, so that a panic in it is recovered too.
+		done <- solved{answer, answer.String(), nil}
+	}()
+
+	select {
+	case s := <-done:
+		res.Answer, res.Output, res.Err = s.answer, s.output, s.err
+	case <-env.Context.Done():
+		res.Err = env.Context.Err()
+	}
+	if errors.Is(res.Err, context.DeadlineExceeded) {
+		res.Err = fmt.Errorf("%w after %v", ErrTimeout, timeout)
+	}
+	res.Duration = time.Since(start)
+	if res.Err != nil {
+		res.Error = res.Err.Error()
+	}
+	return res
+}
@@ -0,0 +1,108 @@
+package challenge
+
+import (
+	"bytes"
+	"cryptopals/util"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+type answer string
+
+func (a answer) String() string {
+	return string(a)
+}
+
+type pointerAnswer struct {
+	text string
+}
+
+func (a *pointerAnswer) String() string {
+	return a.text
+}
+
+func init() {
+	Register(100, 1001, func(env *Env) (fmt.Stringer, error) { return answer("solved"), nil })
+	Register(100, 1002, func(env *Env) (fmt.Stringer, error) { return nil, errors.New("wrong answer") })
+	Register(100, 1003, func(env *Env) (fmt.Stringer, error) { panic("boom") })
+	Register(100, 1004, func(env *Env) (fmt.Stringer, error) { return nil, nil })
+	Register(100, 1005, func(env *Env) (fmt.Stringer, error) { return (*pointerAnswer)(nil), nil })
+	Register(100, 1006, func(env *Env) (fmt.Stringer, error) {
+		time.Sleep(time.Second)
+		return answer("too late"), nil
+	})
+	Register(100, 1007, func(env *Env) (fmt.Stringer, error) {
+		<-env.Context.Done()
+		stopped <- true
+		return nil, env.Context.Err()
+	})
+}
+
+// stopped tells that the solver of challenge 1007 noticed its timeout.
+var stopped = make(chan bool, 1)
+
+func TestSelect(t *testing.T) {
+	selected, err := Select("set100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selected) != 7 || selected[0].Number != 1001 || selected[6].Number != 1007 {
+		t.Fatalf("Expected challenges 1001 to 1007, got %v", selected)
+	}
+	if selected, err := Select("1002"); err != nil || len(selected) != 1 || selected[0].Number != 1002 {
+		t.Fatalf("Expected challenge 1002, got %v and %v", selected, err)
+	}
+	for _, spec := range []string{"999", "set99", "setX", "twelve"} {
+		if _, err := Select(spec); err == nil {
+			t.Fatalf("Expected an error for %q", spec)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	selected, _ := Select("set100")
+	expected := []struct {
+		output string
+		err    string
+	}{
+		{"solved", ""},
+		{"", "wrong answer"},
+		{"", "panic: boom"},
+		{"", "no answer"},
+		{"", "panic:"},
+		{"", "timed out"},
+		{"", "timed out"},
+	}
+	for i, c := range selected {
+		res := Run(c, Env{}, 100*time.Millisecond)
+		if res.Output != expected[i].output {
+			t.Fatalf("Expected output %q for challenge %d, got %q", expected[i].output, c.Number, res.Output)
+		}
+		if expected[i].err == "" && res.Err != nil || expected[i].err != "" && (res.Err == nil || !strings.Contains(res.Error, expected[i].err)) {
+			t.Fatalf("Expected error %q for challenge %d, got %v", expected[i].err, c.Number, res.Err)
+		}
+	}
+	if res := Run(selected[5], Env{}, 10*time.Millisecond); !errors.Is(res.Err, ErrTimeout) {
+		t.Fatalf("Expected %v, got %v", ErrTimeout, res.Err)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the solver of challenge 1007 to stop after the timeout")
+	}
+}
+
+func TestNewEnv(t *testing.T) {
+	draw := func(seed int64, number int) []byte {
+		return util.RandBytesFrom(NewEnv(seed, number, nil, nil).Random, 16)
+	}
+	if !bytes.Equal(draw(7, 12), draw(7, 12)) {
+		t.Fatalf("Expected the same randomness for the same seed and challenge")
+	}
+	if bytes.Equal(draw(7, 12), draw(7, 13)) || bytes.Equal(draw(7, 12), draw(8, 12)) {
+		t.Fatalf("Expected different randomness for different seeds or challenges")
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"cryptopals/challenge"
+	"cryptopals/score"
+	_ "cryptopals/set1"
+	_ "cryptopals/set2"
+	_ "cryptopals/set3"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
+
+const usage = `usage:
+  cryptopals list [-json]
+  cryptopals run [flags] <challenge number | setN | all>...
+`
+
+func fail(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(2)
+}
+
+func list(args []string) {
+	flags := flag.NewFlagSet("list", flag.ExitOnError)
+	asJson := flags.Bool("json", false, "print the list as JSON")
+	flags.Parse(args)
+
+	type entry struct {
+		Set    int `json:"set"`
+		Number int `json:"challenge"`
+	}
+	var entries []entry
+	for _, c := range challenge.All() {
+		entries = append(entries, entry{c.Set, c.Number})
+	}
+	if *asJson {
+		json.NewEncoder(os.Stdout).Encode(entries)
+		return
+	}
+	for _, e := range entries {
+		fmt.Printf("set%d %d\n", e.Set, e.Number)
+	}
+}
+
+func run(args []string) bool {
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	asJson := flags.Bool("json", false, "print the results as JSON")
+	timeout := flags.Duration("timeout", time.Minute, "time limit for every challenge, 0 for none")
+	seed := flags.Int64("seed", 0, "seed of the random sources, to make a run replayable; seeded runs use math/rand, so their oracle keys are predictable (crypto/rand by default)")
+	modelName := flags.String("model", "trigrams", "scorer for single-byte XOR and for ranking the plaintexts of repeating-key XOR: a built-in one, a saved model file or its name in -models")
+	columnModelName := flags.String("column-model", "chi", "scorer for the columns of repeating-key XOR")
+	flags.StringVar(&score.ModelDir, "models", score.ModelDir, "directory with saved models")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		fail(usage)
+	}
+
+	var challenges []challenge.Challenge
+	for _, spec := range flags.Args() {
+		selected, err := challenge.Select(spec)
+		if err != nil {
+			fail("%v", err)
+		}
+		challenges = append(challenges, selected...)
+	}
+
+	model, err := score.Load(*modelName)
+	if err != nil {
+		fail("%v", err)
+	}
+	columnModel, err := score.Load(*columnModelName)
+	if err != nil {
+		fail("%v", err)
+	}
+
+	ok := true
+	var results []challenge.Result
+	for _, c := range challenges {
+		res := challenge.Run(c, challenge.NewEnv(*seed, c.Number, model, columnModel), *timeout)
+		res.Seed = *seed
+		ok = ok && res.Err == nil
+		if *asJson {
+			results = append(results, res)
+			continue
+		}
+		if res.Output != "" {
+			fmt.Println(res.Output)
+		}
+		if res.Err != nil && res.Seed != 0 {
+			fmt.Printf("--- challenge %d FAILED after %v: %v (replay with -seed %d)\n", res.Number, res.Duration.Round(time.Millisecond), res.Err, res.Seed)
+		} else if res.Err != nil {
+			fmt.Printf("--- challenge %d FAILED after %v: %v\n", res.Number, res.Duration.Round(time.Millisecond), res.Err)
+		} else {
+			fmt.Printf("--- challenge %d ok in %v\n", res.Number, res.Duration.Round(time.Millisecond))
+		}
+	}
+	if *asJson {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(results)
+	}
+	return ok
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fail(usage)
+	}
+	switch os.Args[1] {
+	case "list":
+		list(os.Args[2:])
+	case "run":
+		if !run(os.Args[2:]) {
+			os.Exit(1)
+		}
+	default:
+		fail(usage)
+	}
+}
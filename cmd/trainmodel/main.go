@@ -0,0 +1,43 @@
+package main
+
+import (
+	"cryptopals/score"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	n := flag.Int("n", 3, "n-gram length (1 gives a plain byte frequency model)")
+	output := flag.String("o", "", "output file (default: <models dir>/<corpus name>.ngram)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-n N] [-o model.ngram] corpus.txt\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 || *n <= 0 || *n > score.MaxSavedN {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	corpus := flag.Arg(0)
+	if *output == "" {
+		name := filepath.Base(corpus)
+		name = name[:len(name)-len(filepath.Ext(name))]
+		*output = filepath.Join(score.ModelDir, name+score.ModelExt)
+		if err := os.MkdirAll(score.ModelDir, 0o755); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	model, err := score.TrainNGramFile(*n, corpus)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := model.Save(*output); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Saved a %d-gram model with %d distinct n-grams to %s\n", *n, len(model.LogProb), *output)
+}
@@ -0,0 +1,55 @@
+package score
+
+// Relative frequencies of the letters in English text.
+var englishLetters = [26]float64{
+	0.08167, 0.01492, 0.02782, 0.04253, 0.12702, 0.02228, 0.02015, 0.06094, 0.06966,
+	0.00153, 0.00772, 0.04025, 0.02406, 0.06749, 0.07507, 0.01929, 0.00095, 0.05987,
+	0.06327, 0.09056, 0.02758, 0.00978, 0.02360, 0.00150, 0.01974, 0.00074,
+}
+
+// Shares of the byte classes besides letters. Everything else is assumed to be letters.
+const (
+	spaceShare       = 0.17
+	punctuationShare = 0.0499
+	binaryShare      = 0.0001
+	letterShare      = 1 - spaceShare - punctuationShare - binaryShare
+)
+
+// ChiSquared is Pearson's chi-squared statistic of the candidate against English, with letters
+// compared case-insensitively, and spaces, other printable characters and binary bytes as extra classes.
+var ChiSquared Scorer = Func(chiSquared)
+
+func chiSquared(candidate []byte) float64 {
+	if len(candidate) == 0 {
+		return 0
+	}
+
+	var letters [26]int
+	spaces, punctuation, binary := 0, 0, 0
+	for _, b := range candidate {
+		switch {
+		case 'a' <= b && b <= 'z':
+			letters[b-'a']++
+		case 'A' <= b && b <= 'Z':
+			letters[b-'A']++
+		case b == ' ':
+			spaces++
+		case isPrintable(b):
+			punctuation++
+		default:
+			binary++
+		}
+	}
+
+	n := float64(len(candidate))
+	term := func(observed int, share float64) float64 {
+		expected := share * n
+		delta := float64(observed) - expected
+		return delta * delta / expected
+	}
+	res := term(spaces, spaceShare) + term(punctuation, punctuationShare) + term(binary, binaryShare)
+	for i, count := range letters {
+		res += term(count, letterShare*englishLetters[i])
+	}
+	return res
+}
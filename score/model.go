@@ -0,0 +1,181 @@
+package score
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// A saved model is the magic string, followed by uvarints for N, the total number of n-grams and
+// the number of distinct ones, and then every distinct n-gram as N raw bytes and a uvarint count.
+// N-grams are taken over bytes, so UTF-8 text is modelled by its byte sequences.
+const modelMagic = "CPNG1"
+
+const (
+	// MaxSavedN is the longest n-gram a saved model may have.
+	MaxSavedN = 8
+	// maxModelHint caps the map size preallocated from the header of a saved model.
+	maxModelHint = 1 << 16
+)
+
+func TrainNGramFile(n int, fileName string) (*NGram, error) {
+	corpus, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return TrainNGram(n, corpus), nil
+}
+
+func (m *NGram) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	written := int64(0)
+	buf := make([]byte, binary.MaxVarintLen64)
+	write := func(p []byte) {
+		n, _ := bw.Write(p)
+		written += int64(n)
+	}
+	writeUvarint := func(x int) {
+		write(buf[:binary.PutUvarint(buf, uint64(x))])
+	}
+
+	write([]byte(modelMagic))
+	writeUvarint(m.N)
+	writeUvarint(m.total)
+	writeUvarint(len(m.counts))
+	// Sort the n-grams so that the same corpus always produces the same file.
+	grams := make([]string, 0, len(m.counts))
+	for gram := range m.counts {
+		grams = append(grams, gram)
+	}
+	sort.Strings(grams)
+	for _, gram := range grams {
+		write([]byte(gram))
+		writeUvarint(m.counts[gram])
+	}
+	return written, bw.Flush()
+}
+
+func ReadNGram(r io.Reader) (*NGram, error) {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(modelMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != modelMagic {
+		return nil, fmt.Errorf("not an n-gram model")
+	}
+	readUvarint := func() (uint64, error) {
+		x, err := binary.ReadUvarint(br)
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return x, err
+	}
+
+	n, err := readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	total, err := readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	distinct, err := readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	// The header comes straight from the file, so it is validated before anything is allocated.
+	maxDistinct := uint64(math.MaxUint64)
+	if n < 8 {
+		maxDistinct = 1 << (8 * n)
+	}
+	if n == 0 || n > MaxSavedN || total > math.MaxInt || distinct > total || distinct > maxDistinct {
+		return nil, fmt.Errorf("corrupted n-gram model: n = %d, %d distinct out of %d", n, distinct, total)
+	}
+
+	// A lying header can't make the map huge upfront, it only grows with the n-grams actually read.
+	hint := distinct
+	if hint > maxModelHint {
+		hint = maxModelHint
+	}
+	counts := make(map[string]int, hint)
+	gram := make([]byte, n)
+	sum := uint64(0)
+	for i := uint64(0); i < distinct; i++ {
+		if _, err := io.ReadFull(br, gram); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		count, err := readUvarint()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := counts[string(gram)]; ok || count == 0 || count > total-sum {
+			return nil, fmt.Errorf("corrupted n-gram model: n-gram %q with count %d", gram, count)
+		}
+		sum += count
+		counts[string(gram)] = int(count)
+	}
+	return newNGram(int(n), counts, int(total)), nil
+}
+
+func (m *NGram) Save(fileName string) error {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	if _, err := m.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func LoadNGram(fileName string) (*NGram, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	res, err := ReadNGram(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fileName, err)
+	}
+	return res, nil
+}
+
+// ModelDir is where Load looks for saved models that are referred to by name.
+var ModelDir = "models"
+
+// ModelExt is the extension of the saved models in ModelDir.
+const ModelExt = ".ngram"
+
+var builtins = map[string]Scorer{
+	"default":   Default,
+	"chi":       ChiSquared,
+	"printable": Printable,
+	"unigrams":  EnglishUnigrams,
+	"bigrams":   EnglishBigrams,
+	"trigrams":  EnglishTrigrams,
+}
+
+// Load returns a built-in scorer by its name, or else a saved model, given either
+// as a path or as a name of a file in ModelDir without the extension.
+func Load(name string) (Scorer, error) {
+	if scorer, ok := builtins[name]; ok {
+		return scorer, nil
+	}
+	if _, err := os.Stat(name); err == nil {
+		return LoadNGram(name)
+	}
+	fileName := filepath.Join(ModelDir, name+ModelExt)
+	if _, err := os.Stat(fileName); err != nil {
+		return nil, fmt.Errorf("unknown model %q", name)
+	}
+	return LoadNGram(fileName)
+}
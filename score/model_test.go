@@ -0,0 +1,103 @@
+package score
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestModelRoundtrip(t *testing.T) {
+	// Byte n-grams work just as well for UTF-8 text.
+	corpus := []byte("Съешь же ещё этих мягких французских булок, да выпей чаю.")
+	model := TrainNGram(3, corpus)
+
+	var buf bytes.Buffer
+	if _, err := model.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var again bytes.Buffer
+	model.WriteTo(&again)
+	if !bytes.Equal(buf.Bytes(), again.Bytes()) {
+		t.Fatalf("Saving the same model twice gave different files")
+	}
+
+	loaded, err := ReadNGram(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.N != model.N || loaded.Floor != model.Floor || !reflect.DeepEqual(loaded.LogProb, model.LogProb) {
+		t.Fatalf("The loaded model differs from the saved one")
+	}
+	if _, err := ReadNGram(bytes.NewReader(again.Bytes()[:again.Len()-3])); err == nil {
+		t.Fatalf("Expected an error for a truncated model")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	oldDir := ModelDir
+	ModelDir = dir
+	defer func() { ModelDir = oldDir }()
+
+	corpus := filepath.Join(dir, "corpus.txt")
+	os.WriteFile(corpus, []byte("Der Zug hat Verspätung, wir warten auf dem Bahnsteig."), 0o644)
+	model, err := TrainNGramFile(2, corpus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := model.Save(filepath.Join(dir, "german"+ModelExt)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"german", filepath.Join(dir, "german"+ModelExt)} {
+		loaded, err := Load(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if loaded.(*NGram).N != 2 {
+			t.Fatalf("%s: unexpected model %v", name, loaded)
+		}
+	}
+	if scorer, err := Load("chi"); err != nil || scorer == nil {
+		t.Fatalf("Expected the built-in chi-squared scorer, got %v", err)
+	}
+	if _, err := Load("klingon"); err == nil {
+		t.Fatalf("Expected an error for an unknown model")
+	}
+}
+
+func TestReadNGramCorrupted(t *testing.T) {
+	uvarints := func(xs ...uint64) []byte {
+		res := []byte(modelMagic)
+		for _, x := range xs {
+			res = binary.AppendUvarint(res, x)
+		}
+		return res
+	}
+	corrupted := map[string][]byte{
+		"no magic":          []byte("CPNG"),
+		"truncated header":  uvarints(3, 10)[:len(modelMagic)+2],
+		"zero n":            uvarints(0, 1, 1),
+		"huge n":            uvarints(math.MaxUint64, 1, 1),
+		"n too long":        uvarints(MaxSavedN+1, 1, 1),
+		"negative total":    uvarints(1, math.MaxUint64, 1),
+		"negative distinct": uvarints(1, 10, math.MaxUint64),
+		"too many distinct": uvarints(1, 1000, 257),
+		"more than total":   uvarints(2, 5, 6),
+		"huge distinct":     uvarints(8, math.MaxInt64, math.MaxInt64),
+		"truncated n-grams": append(uvarints(2, 5, 2), "ab"...),
+		"count above total": append(append(uvarints(1, 5, 1), 'a'), binary.AppendUvarint(nil, 6)...),
+		"zero count":        append(append(uvarints(1, 5, 1), 'a'), 0),
+		"duplicate n-grams": append(append(append(uvarints(1, 5, 2), 'a', 1), 'a'), 1),
+		"truncated n-gram":  append(uvarints(3, 5, 1), 'a'),
+	}
+	for name, data := range corrupted {
+		if _, err := ReadNGram(bytes.NewReader(data)); err == nil {
+			t.Fatalf("Expected an error for %s", name)
+		}
+	}
+}
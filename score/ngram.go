@@ -0,0 +1,94 @@
+package score
+
+import (
+	"math"
+)
+
+// NGram scores a candidate by the average negative log-likelihood of its overlapping n-grams
+// under a model trained on a corpus. Letters are folded to lower case.
+type NGram struct {
+	N       int
+	LogProb map[string]float64
+	// Floor is the log-probability of an n-gram that never occurred in the corpus.
+	Floor float64
+
+	// The raw counts are kept so that the model can be saved without losing precision.
+	counts map[string]int
+	total  int
+}
+
+func fold(b byte) byte {
+	if 'A' <= b && b <= 'Z' {
+		return b + 'a' - 'A'
+	}
+	return b
+}
+
+func foldAll(input []byte) []byte {
+	res := make([]byte, len(input))
+	for i, b := range input {
+		res[i] = fold(b)
+	}
+	return res
+}
+
+func TrainNGram(n int, corpus []byte) *NGram {
+	counts := make(map[string]int)
+	total := 0
+	folded := foldAll(corpus)
+	for i := 0; i+n <= len(folded); i++ {
+		counts[string(folded[i:i+n])]++
+		total++
+	}
+	return newNGram(n, counts, total)
+}
+
+func newNGram(n int, counts map[string]int, total int) *NGram {
+	res := &NGram{N: n, LogProb: make(map[string]float64, len(counts)), counts: counts, total: total}
+	denominator := float64(total)
+	if total == 0 {
+		denominator = 1
+	}
+	for gram, count := range counts {
+		res.LogProb[gram] = math.Log(float64(count) / denominator)
+	}
+	res.Floor = math.Log(0.01 / denominator)
+	return res
+}
+
+func (m *NGram) Score(candidate []byte) float64 {
+	folded := foldAll(candidate)
+	if len(folded) < m.N {
+		return -m.Floor
+	}
+	res := 0.0
+	for i := 0; i+m.N <= len(folded); i++ {
+		logProb, ok := m.LogProb[string(folded[i:i+m.N])]
+		if !ok {
+			logProb = m.Floor
+		}
+		res -= logProb
+	}
+	return res / float64(len(folded)-m.N+1)
+}
+
+// A small sample of plain English. It is enough to tell text apart from random bytes;
+// train a model on a real corpus for anything more demanding.
+const englishSample = cryptopalsIntro + `
+The quick brown fox jumps over the lazy dog. It was the best of times, it was the worst of times.
+A sentence is made of words, and the words are separated by single spaces. Most of the words in
+a typical text are short and common: the, and, of, to, in, is, it, that, was, for, on, are, with,
+as, his, they, be, at, one, have, this, from, or, had, by, but, what, some, we, can, out, other,
+were, all, there, when, up, use, your, how, said, an, each, she, which, do, their, time, if, will,
+way, about, many, then, them, write, would, like, so, these, her, long, make, thing, see, him, two,
+has, look, more, day, could, go, come, did, number, sound, no, most, people, my, over, know, water,
+than, call, first, who, may, down, side, been, now, find. When the weather is nice, we go for a walk
+along the river and talk about everything that happened during the week. Nobody knows what will
+happen next, but everyone hopes that it will be something good.
+`
+
+var (
+	EnglishUnigrams = TrainNGram(1, []byte(englishSample))
+	EnglishBigrams  = TrainNGram(2, []byte(englishSample))
+	EnglishTrigrams = TrainNGram(3, []byte(englishSample))
+)
@@ -0,0 +1,77 @@
+package score
+
+import (
+	"math"
+)
+
+// Scorer rates how much a candidate plaintext looks like the expected language.
+// Lower scores are better, so that a perfect match would score zero.
+type Scorer interface {
+	Score(candidate []byte) float64
+}
+
+type Func func(candidate []byte) float64
+
+func (f Func) Score(candidate []byte) float64 {
+	return f(candidate)
+}
+
+// Histogram is the Euclidean distance between the byte counts of a candidate and of a model text.
+type Histogram map[byte]int
+
+func NewHistogram(model []byte) Histogram {
+	result := make(Histogram)
+	for _, b := range model {
+		result[b]++
+	}
+	return result
+}
+
+func (h Histogram) Score(candidate []byte) float64 {
+	other := NewHistogram(candidate)
+	result := 0
+	for i := 0; i <= math.MaxUint8; i++ {
+		delta := h[byte(i)] - other[byte(i)]
+		result += delta * delta
+	}
+	return math.Sqrt(float64(result))
+}
+
+const cryptopalsIntro = `This is a different way to learn about crypto than taking a class or reading a book. We give you problems to solve. They're derived from weaknesses in real-world systems and modern cryptographic constructions. We give you enough info to learn about the underlying crypto concepts yourself. When you're finished, you'll not only have learned a good deal about how cryptosystems are built, but you'll also understand how they're attacked.`
+
+// Default is the histogram of the cryptopals introduction, which set 1 has always used.
+var Default Scorer = NewHistogram([]byte(cryptopalsIntro))
+
+func isPrintable(b byte) bool {
+	return 0x20 <= b && b <= 0x7e || b == '\n' || b == '\r' || b == '\t'
+}
+
+// Printable is the share of bytes that are neither printable ASCII nor common whitespace.
+var Printable Scorer = Func(func(candidate []byte) float64 {
+	if len(candidate) == 0 {
+		return 0
+	}
+	bad := 0
+	for _, b := range candidate {
+		if !isPrintable(b) {
+			bad++
+		}
+	}
+	return float64(bad) / float64(len(candidate))
+})
+
+type Weighted struct {
+	Scorer Scorer
+	Weight float64
+}
+
+// Sum adds up the weighted scores, e.g. to combine a language model with a Printable penalty.
+func Sum(parts ...Weighted) Scorer {
+	return Func(func(candidate []byte) float64 {
+		res := 0.0
+		for _, part := range parts {
+			res += part.Weight * part.Scorer.Score(candidate)
+		}
+		return res
+	})
+}
@@ -0,0 +1,66 @@
+package score
+
+import (
+	"encoding/hex"
+	"math"
+	"testing"
+)
+
+func bestSingleByteKey(ciphertext []byte, scorer Scorer) byte {
+	bestKey, bestScore := 0, math.Inf(1)
+	candidate := make([]byte, len(ciphertext))
+	for key := 0; key <= math.MaxUint8; key++ {
+		for i, b := range ciphertext {
+			candidate[i] = b ^ byte(key)
+		}
+		if s := scorer.Score(candidate); s < bestScore {
+			bestKey, bestScore = key, s
+		}
+	}
+	return byte(bestKey)
+}
+
+func TestScorers(t *testing.T) {
+	// Challenge 3.
+	ciphertext, _ := hex.DecodeString("1b37373331363f78151b7f2b783431333d78397828372d363c78373e783a393b3736")
+	scorers := map[string]Scorer{
+		"default":    Default,
+		"chi":        ChiSquared,
+		"unigrams":   EnglishUnigrams,
+		"bigrams":    EnglishBigrams,
+		"trigrams":   EnglishTrigrams,
+		"printable":  Sum(Weighted{EnglishBigrams, 1}, Weighted{Printable, 100}),
+		"chiAndFunc": Sum(Weighted{ChiSquared, 1}, Weighted{Func(func([]byte) float64 { return 0 }), 1}),
+	}
+	for name, scorer := range scorers {
+		if key := bestSingleByteKey(ciphertext, scorer); key != 88 {
+			t.Fatalf("%s: expected key 88, got %d", name, key)
+		}
+	}
+}
+
+func TestPrintable(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"", 0},
+		{"Cooking MC's\tlike a pound of bacon\r\n", 0},
+		{"ab\x00\xff", 0.5},
+	}
+	for _, tt := range tests {
+		if actual := Printable.Score([]byte(tt.input)); actual != tt.expected {
+			t.Fatalf("Printable(%q): expected %g, got %g", tt.input, tt.expected, actual)
+		}
+	}
+}
+
+func TestNGram(t *testing.T) {
+	model := TrainNGram(2, []byte("abab"))
+	if len(model.LogProb) != 2 || model.LogProb["ab"] <= model.LogProb["ba"] {
+		t.Fatalf("Unexpected model %v", model.LogProb)
+	}
+	if model.Score([]byte("ABAB")) >= model.Score([]byte("abba")) {
+		t.Fatalf("Expected a seen text to score better than an unseen one")
+	}
+}
@@ -0,0 +1,229 @@
+package set1
+
+import (
+	"crypto/aes"
+	"cryptopals/challenge"
+	"cryptopals/score"
+	"cryptopals/util"
+	"cryptopals/xor"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type Result1 struct {
+	Base64 string
+}
+
+func (r Result1) String() string {
+	return fmt.Sprintf("Challenge 1: %s", r.Base64)
+}
+
+func Solve1() (Result1, error) {
+	rawStr, err := hex.DecodeString("49276d206b696c6c696e6720796f757220627261696e206c696b65206120706f69736f6e6f7573206d757368726f6f6d")
+	if err != nil {
+		return Result1{}, err
+	}
+	return Result1{base64.StdEncoding.EncodeToString(rawStr)}, nil
+}
+
+type Result2 struct {
+	Hex string
+}
+
+func (r Result2) String() string {
+	return fmt.Sprintf("Challenge 2: %s", r.Hex)
+}
+
+func Solve2() (Result2, error) {
+	str1, err := hex.DecodeString("1c0111001f010100061a024b53535009181c")
+	if err != nil {
+		return Result2{}, err
+	}
+	str2, err := hex.DecodeString("686974207468652062756c6c277320657965")
+	if err != nil {
+		return Result2{}, err
+	}
+	return Result2{hex.EncodeToString(xor.RepeatingKey(str1, str2))}, nil
+}
+
+type Result3 struct {
+	Key       byte
+	Plaintext string
+	Score     float64
+}
+
+func (r Result3) String() string {
+	return fmt.Sprintf("Challenge 3: decoded with key %d: <%s> (READABILITY = %g)", r.Key, r.Plaintext, r.Score)
+}
+
+func Solve3(scorer score.Scorer) (Result3, error) {
+	rawStr, err := hex.DecodeString("1b37373331363f78151b7f2b783431333d78397828372d363c78373e783a393b3736")
+	if err != nil {
+		return Result3{}, err
+	}
+	res := xor.BreakSingleByte(rawStr, scorer)
+	return Result3{res.Key, string(res.Plaintext), res.Score}, nil
+}
+
+type Result4 struct {
+	Line       int
+	Ciphertext string
+	Key        byte
+	Plaintext  string
+	Score      float64
+}
+
+func (r Result4) String() string {
+	return fmt.Sprintf(
+		"Challenge 4: decoded %s with key %d: <%s> (READABILITY = %g)",
+		r.Ciphertext, r.Key, strings.TrimSpace(r.Plaintext), r.Score,
+	)
+}
+
+func Solve4(scorer score.Scorer) (Result4, error) {
+	f, err := os.Open("4.txt")
+	if err != nil {
+		return Result4{}, err
+	}
+	defer f.Close()
+	detections, err := xor.DetectSingleByte(f, xor.DetectOptions{TopK: 1, Scorer: scorer, Encoding: util.HexEncoding})
+	if err != nil {
+		return Result4{}, err
+	}
+	if len(detections) == 0 {
+		return Result4{}, fmt.Errorf("4.txt is empty")
+	}
+	best := detections[0]
+	return Result4{
+		Line:       best.Line,
+		Ciphertext: hex.EncodeToString(xor.RepeatingKey(best.Plaintext, []byte{best.Key})),
+		Key:        best.Key,
+		Plaintext:  string(best.Plaintext),
+		Score:      best.Score,
+	}, nil
+}
+
+type Result5 struct {
+	Hex string
+}
+
+func (r Result5) String() string {
+	return fmt.Sprintf("Challenge 5: %s", r.Hex)
+}
+
+func Solve5() (Result5, error) {
+	str := `Burning 'em, if you ain't quick and nimble I go crazy when I hear a cymbal`
+	xored := xor.RepeatingKey([]byte(str), []byte("ICE"))
+	return Result5{hex.EncodeToString(xored)}, nil
+}
+
+type Result6 struct {
+	Key        string
+	Plaintext  string
+	Confidence []float64
+}
+
+func (r Result6) String() string {
+	lines := strings.Split(r.Plaintext, "\n")
+	return fmt.Sprintf("Challenge 6: key = %s, first line = <%s>", r.Key, strings.TrimSpace(lines[0]))
+}
+
+func Solve6(scorer score.Scorer, columnScorer score.Scorer) (Result6, error) {
+	rawStr, err := util.ReadBlob("6.txt", util.Base64Encoding)
+	if err != nil {
+		return Result6{}, err
+	}
+
+	candidates, err := xor.BreakRepeatingKey(rawStr, xor.Options{MaxKeySize: 100, Scorer: scorer, ColumnScorer: columnScorer})
+	if err != nil {
+		return Result6{}, err
+	}
+	best := candidates[0]
+	return Result6{string(best.Key), string(best.Plaintext), best.Confidence}, nil
+}
+
+type Result7 struct {
+	Plaintext string
+}
+
+func (r Result7) String() string {
+	lines := strings.Split(r.Plaintext, "\n")
+	return fmt.Sprintf(
+		"Challenge 7: first line = <%s>, last line = <%s>",
+		strings.TrimSpace(lines[0]), strings.TrimSpace(lines[len(lines)-2]),
+	)
+}
+
+func Solve7() (Result7, error) {
+	cipherText, err := util.ReadBlob("7.txt", util.Base64Encoding)
+	if err != nil {
+		return Result7{}, err
+	}
+	block, err := aes.NewCipher([]byte("YELLOW SUBMARINE"))
+	if err != nil {
+		return Result7{}, err
+	}
+
+	result := ""
+	buffer := make([]byte, aes.BlockSize)
+	for i := 0; i < len(cipherText); i += aes.BlockSize {
+		block.Decrypt(buffer, cipherText[i:i+aes.BlockSize])
+		result += string(buffer)
+	}
+	return Result7{result}, nil
+}
+
+type EcbLine struct {
+	Line       int
+	Ciphertext string
+	Repeats    int
+}
+
+type Result8 struct {
+	Detected []EcbLine
+}
+
+func (r Result8) String() string {
+	var lines []string
+	for _, d := range r.Detected {
+		lines = append(lines, fmt.Sprintf("Challenge 8: %s: %d repeated blocks", d.Ciphertext, d.Repeats))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func Solve8() (Result8, error) {
+	records, err := util.ReadRecords("8.txt", util.HexEncoding)
+	if err != nil {
+		return Result8{}, err
+	}
+	ciphertexts := make([][]byte, len(records))
+	for i, record := range records {
+		ciphertexts[i] = record.Data
+	}
+	ranked, err := util.RankEcb(ciphertexts, aes.BlockSize)
+	if err != nil {
+		return Result8{}, err
+	}
+	var res Result8
+	for _, r := range ranked {
+		if !r.IsEcb() {
+			break
+		}
+		res.Detected = append(res.Detected, EcbLine{records[r.Index].Line, hex.EncodeToString(ciphertexts[r.Index]), r.Repeats})
+	}
+	return res, nil
+}
+
+func init() {
+	challenge.Register(1, 1, func(env *challenge.Env) (fmt.Stringer, error) { return Solve1() })
+	challenge.Register(1, 2, func(env *challenge.Env) (fmt.Stringer, error) { return Solve2() })
+	challenge.Register(1, 3, func(env *challenge.Env) (fmt.Stringer, error) { return Solve3(env.Model) })
+	challenge.Register(1, 4, func(env *challenge.Env) (fmt.Stringer, error) { return Solve4(env.Model) })
+	challenge.Register(1, 5, func(env *challenge.Env) (fmt.Stringer, error) { return Solve5() })
+	challenge.Register(1, 6, func(env *challenge.Env) (fmt.Stringer, error) { return Solve6(env.Model, env.ColumnModel) })
+	challenge.Register(1, 7, func(env *challenge.Env) (fmt.Stringer, error) { return Solve7() })
+	challenge.Register(1, 8, func(env *challenge.Env) (fmt.Stringer, error) { return Solve8() })
+}
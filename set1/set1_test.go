@@ -0,0 +1,99 @@
+package set1
+
+import (
+	"cryptopals/score"
+	"os"
+	"strings"
+	"testing"
+)
+
+// The data files are in the root of the repository.
+func TestMain(m *testing.M) {
+	if err := os.Chdir(".."); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+const firstLine = "I'm back and I'm ringin' the bell"
+
+func TestSolve1(t *testing.T) {
+	res, err := Solve1()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "SSdtIGtpbGxpbmcgeW91ciBicmFpbiBsaWtlIGEgcG9pc29ub3VzIG11c2hyb29t"; res.Base64 != expected {
+		t.Fatalf("Expected %v, got %v", expected, res.Base64)
+	}
+}
+
+func TestSolve2(t *testing.T) {
+	res, err := Solve2()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "746865206b696420646f6e277420706c6179"; res.Hex != expected {
+		t.Fatalf("Expected %v, got %v", expected, res.Hex)
+	}
+}
+
+func TestSolve3(t *testing.T) {
+	res, err := Solve3(score.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "Cooking MC's like a pound of bacon"; res.Key != 88 || res.Plaintext != expected {
+		t.Fatalf("Expected key 88 and %q, got %v and %q", expected, res.Key, res.Plaintext)
+	}
+}
+
+func TestSolve4(t *testing.T) {
+	res, err := Solve4(score.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "Now that the party is jumping\n"; res.Line != 171 || res.Key != 53 || res.Plaintext != expected {
+		t.Fatalf("Expected line 171, key 53 and %q, got line %v, key %v and %q", expected, res.Line, res.Key, res.Plaintext)
+	}
+}
+
+func TestSolve5(t *testing.T) {
+	res, err := Solve5()
+	expected := "0b3637272a2b2e63622c2e69692a23693a2a3c6324202d623d63343c2a26226324272765272a282b2f20690a652e2c652a3124333a653e2b2027630c692b20283165286326302e27282f"
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Hex != expected {
+		t.Fatalf("Expected %v, got %v", expected, res.Hex)
+	}
+}
+
+func TestSolve6(t *testing.T) {
+	res, err := Solve6(score.EnglishTrigrams, score.ChiSquared)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "Terminator X: Bring the noise"; res.Key != expected || !strings.HasPrefix(res.Plaintext, firstLine) {
+		t.Fatalf("Expected key %q and a plaintext starting with %q, got %q and %q", expected, firstLine, res.Key, res.Plaintext)
+	}
+}
+
+func TestSolve7(t *testing.T) {
+	res, err := Solve7()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(res.Plaintext, firstLine) || !strings.Contains(res.Plaintext, "Play that funky music \n") {
+		t.Fatalf("Expected a plaintext from %q to %q, got %q", firstLine, "Play that funky music \n", res.Plaintext)
+	}
+}
+
+func TestSolve8(t *testing.T) {
+	res, err := Solve8()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Detected) != 1 || res.Detected[0].Line != 133 || res.Detected[0].Repeats != 3 {
+		t.Fatalf("Expected line 133 with 3 repeats only, got %+v", res.Detected)
+	}
+}
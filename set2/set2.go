@@ -0,0 +1,452 @@
+package set2
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"cryptopals/challenge"
+	"cryptopals/util"
+	"fmt"
+	"strconv"
+)
+
+type Result9 struct {
+	Padded string
+}
+
+func (r Result9) String() string {
+	return fmt.Sprintf("Challenge 9: %q", r.Padded)
+}
+
+func Solve9() (Result9, error) {
+	orig := "YELLOW SUBMARINE"
+	padded, err := util.PKCS7Pad([]byte(orig), 20)
+	if err != nil {
+		return Result9{}, err
+	}
+	return Result9{string(padded)}, nil
+}
+
+type Result10 struct {
+	Plaintext string
+}
+
+func (r Result10) String() string {
+	return fmt.Sprintf("Challenge 10: %q", r.Plaintext)
+}
+
+func Solve10() (Result10, error) {
+	content, err := util.ReadBase64File("10.txt")
+	if err != nil {
+		return Result10{}, err
+	}
+	key := []byte("YELLOW SUBMARINE")
+	iv := make([]byte, util.AesBlockSize)
+	decoded, err := util.AesCbcDecrypt(content, key, iv)
+	if err != nil {
+		return Result10{}, err
+	}
+	return Result10{string(decoded)}, nil
+}
+
+type Result11 struct {
+	Guessed  int
+	Attempts int
+}
+
+func (r Result11) String() string {
+	return fmt.Sprintf("Challenge 11: guessed %d times out of %d", r.Guessed, r.Attempts)
+}
+
+func Solve11(random util.Rand) (Result11, error) {
+	ecbCbcOracle := func(plaintext []byte) (res []byte, isCbc bool, err error) {
+		prefix := util.RandBytesFrom(random, 5+random.Intn(6))
+		suffix := util.RandBytesFrom(random, 5+random.Intn(6))
+		extended := append(append(prefix, plaintext...), suffix...)
+		key := util.RandBytesFrom(random, util.AesBlockSize)
+		if random.Intn(2) == 1 {
+			// CBC
+			iv := util.RandBytesFrom(random, util.AesBlockSize)
+			res, err := util.AesCbcEncrypt(extended, key, iv)
+			return res, true, err
+		} else {
+			// ECB
+			res, err := util.AesEcbEncrypt(extended, key)
+			return res, false, err
+		}
+	}
+
+	guessIsCbc := func() (bool, error) {
+		payload := bytes.Repeat([]byte("A"), aes.BlockSize*3)
+		encrypted, oracleIsCbc, err := ecbCbcOracle(payload)
+		if err != nil {
+			return false, err
+		}
+		s, err := util.ScoreEcb(encrypted, util.AesBlockSize)
+		if err != nil {
+			return false, err
+		}
+		return oracleIsCbc == !s.IsEcb(), nil
+	}
+
+	res := Result11{Attempts: 1000}
+	for i := 0; i < res.Attempts; i++ {
+		ok, err := guessIsCbc()
+		if err != nil {
+			return Result11{}, err
+		}
+		if ok {
+			res.Guessed++
+		}
+	}
+	if res.Guessed != res.Attempts {
+		return Result11{}, fmt.Errorf("guessed the mode only %d times out of %d", res.Guessed, res.Attempts)
+	}
+	return res, nil
+}
+
+type Result12 struct {
+	BlockSize int
+	Secret    string
+}
+
+func (r Result12) String() string {
+	return fmt.Sprintf("Challenge 12: %q", r.Secret)
+}
+
+func Solve12(random util.Rand) (Result12, error) {
+	cipher, err := util.NewAes(util.RandBytesFrom(random, util.AesBlockSize))
+	if err != nil {
+		return Result12{}, err
+	}
+	secret, err := util.ReadBase64File("12.txt")
+	if err != nil {
+		return Result12{}, err
+	}
+
+	oracle := func(payload []byte) []byte {
+		plaintext := append(payload, secret...)
+		return cipher.EcbEncrypt(nil, plaintext)
+	}
+
+	paddingBytes := 0
+	rep := func(n int) []byte {
+		return bytes.Repeat([]byte("A"), n)
+	}
+	secretLen := len(oracle(rep(0)))
+	for {
+		paddingBytes++
+		if secretLen != len(oracle(rep(paddingBytes))) {
+			break
+		}
+	}
+	// The ciphertext grows by exactly one block once the padding overflows.
+	a := len(oracle(rep(paddingBytes))) - secretLen
+	secretLen -= paddingBytes
+
+	knownPlaintext := make([]byte, 0)
+	for y := 0; y < secretLen; y++ {
+		x := ((-(y + 1) % a) + a) % a
+		prefix := append(rep(x), knownPlaintext...)
+		codebook := make(map[string]byte)
+		lastBlock := prefix[len(prefix)-(a-1):]
+		for b := 0; b < 256; b++ {
+			payload := append(lastBlock, byte(b))
+			key := string(oracle(payload)[:a])
+			codebook[key] = byte(b)
+		}
+
+		encrypted := oracle(rep(x))
+		targetStart := len(prefix) - (a - 1)
+		target := string(encrypted[targetStart : targetStart+a])
+		restored, ok := codebook[target]
+		if !ok {
+			return Result12{}, fmt.Errorf("failed to restore byte %d", y)
+		}
+		knownPlaintext = append(knownPlaintext, restored)
+	}
+
+	return Result12{a, string(knownPlaintext)}, nil
+}
+
+type Result13 struct {
+	Profile map[string]string
+	Admin   bool
+}
+
+func (r Result13) String() string {
+	return fmt.Sprintf("Challenge 13: isAdminRole(%v) = %v", r.Profile, r.Admin)
+}
+
+func Solve13(random util.Rand) (Result13, error) {
+	userId := 0
+	profileFor := func(email string) (string, error) {
+		userId++
+		res, err := util.EncodeKV([]util.KV{
+			{Key: "email", Value: email},
+			{Key: "uid", Value: strconv.Itoa(userId)},
+			{Key: "role", Value: "user"},
+		}, util.KVOptions{})
+		if err != nil {
+			return "", fmt.Errorf("<%s> is not a valid email: %w", email, err)
+		}
+		return res, nil
+	}
+
+	key := util.RandBytesFrom(random, util.AesBlockSize)
+
+	encryptProfile := func(email string) ([]byte, error) {
+		profile, err := profileFor(email)
+		if err != nil {
+			return nil, err
+		}
+		return util.AesEcbEncrypt([]byte(profile), key)
+	}
+
+	decryptProfile := func(encrypted []byte) (map[string]string, error) {
+		rawProfile, err := util.AesEcbDecrypt(encrypted, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt profile <%v>: %w", encrypted, err)
+		}
+		return util.ParseKV(string(rawProfile))
+	}
+
+	profile1, err := encryptProfile("AAAAAAAAAAAAAA")
+	if err != nil {
+		return Result13{}, err
+	}
+	evil1 := profile1[:2*util.AesBlockSize]
+	profile2, err := encryptProfile("AAAAAAAAAAAAAAAAAAAAAAAAAAadmin")
+	if err != nil {
+		return Result13{}, err
+	}
+	evil2 := profile2[2*util.AesBlockSize : len(profile2)-util.AesBlockSize]
+	evil3 := profile2[:util.AesBlockSize]
+	evil4 := profile1[len(profile1)-util.AesBlockSize:]
+	evil := bytes.Join([][]byte{
+		evil1, evil2, evil3, evil4,
+	}, []byte(""))
+	decrypted, err := decryptProfile(evil)
+	if err != nil {
+		return Result13{}, err
+	}
+
+	isAdminRole := func(profile map[string]string) bool {
+		value, ok := profile["role"]
+		return ok && value == "admin"
+	}
+	if !isAdminRole(decrypted) {
+		return Result13{}, fmt.Errorf("the forged profile %v is not an admin", decrypted)
+	}
+	return Result13{decrypted, true}, nil
+}
+
+type Result14 struct {
+	Secret string
+}
+
+func (r Result14) String() string {
+	return fmt.Sprintf("Challenge 14: %q", r.Secret)
+}
+
+func Solve14(ctx context.Context, random util.Rand) (Result14, error) {
+	cipher, err := util.NewAes(util.RandBytesFrom(random, util.AesBlockSize))
+	if err != nil {
+		return Result14{}, err
+	}
+	secret, err := util.ReadBase64File("12.txt")
+	if err != nil {
+		return Result14{}, err
+	}
+
+	// The oracle is called hundreds of thousands of times, so its output buffer is reused.
+	// Every result is only valid until the next call.
+	var encrypted []byte
+	oracle := func(payload []byte) []byte {
+		// Note that the challenge description is ambiguous: it is not clear if the oracle should
+		// generate the prefix upfront (similar to the AES key), or re-generate the prefix every
+		// time the oracle is called.
+		//
+		// My solution is for the latter version, which is harder to attack.
+		prefix := util.RandBytesFrom(random, random.Intn(42))
+		plaintext := append(prefix, append(payload, secret...)...)
+		encrypted = cipher.EcbEncrypt(encrypted[:0], plaintext)
+		return encrypted
+	}
+
+	// The prefix only has the right length for a fraction of the queries, so every byte value
+	// is tried over and over. Give up if no value wins after this many rounds.
+	const maxRounds = 1000
+	guessChar := func(known []byte) (byte, error) {
+		nextLen := len(known) + 1
+
+		padLen := 0
+		if nextLen%util.AesBlockSize != 0 {
+			padLen = util.AesBlockSize - nextLen%util.AesBlockSize
+		}
+		pad := make([]byte, padLen)
+
+		suffix := append(pad, append(known, byte(0))...)
+		payload := append(suffix[len(suffix)-util.AesBlockSize:], pad...)
+
+		cands := make([]int, 256)
+		for round := 0; round < maxRounds; round++ {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
+			for b := 0; b <= 0xff; b++ {
+				bb := byte(b)
+				payload[util.AesBlockSize-1] = bb
+				encrypted := oracle(payload)
+				if util.HasRepeatedBlock(encrypted, util.AesBlockSize) {
+					cands[bb]++
+					if cands[bb] > 2 {
+						return bb, nil
+					}
+				}
+			}
+		}
+		return 0, fmt.Errorf("failed to restore byte %d in %d rounds", len(known), maxRounds)
+	}
+
+	knownPlaintext := make([]byte, 0)
+	for {
+		guessed, err := guessChar(knownPlaintext)
+		if err != nil {
+			return Result14{}, err
+		}
+		if guessed == 1 {
+			break
+		}
+		knownPlaintext = append(knownPlaintext, guessed)
+	}
+	return Result14{string(knownPlaintext)}, nil
+}
+
+type PaddingCheck struct {
+	Input string
+	Valid bool
+}
+
+type Result15 struct {
+	Checks []PaddingCheck
+}
+
+func (r Result15) String() string {
+	res := "Challenge 15:"
+	for i, c := range r.Checks {
+		if i > 0 {
+			res += ","
+		}
+		res += fmt.Sprintf(" valid(%q) = %v", c.Input, c.Valid)
+	}
+	return res
+}
+
+func Solve15() (Result15, error) {
+	isValidPadding := func(input []byte) bool {
+		_, err := util.PKCS7Unpad(input, aes.BlockSize)
+		return err == nil
+	}
+
+	var res Result15
+	for _, input := range []string{
+		"ICE ICE BABY\x04\x04\x04\x04",
+		"ICE ICE BABY\x05\x05\x05\x05",
+		"ICE ICE BABY\x01\x02\x03\x04",
+	} {
+		res.Checks = append(res.Checks, PaddingCheck{input, isValidPadding([]byte(input))})
+	}
+	return res, nil
+}
+
+type Result16 struct {
+	Cookie   string
+	Admin    bool
+	Attempts int
+}
+
+func (r Result16) String() string {
+	return fmt.Sprintf("%q\nChallenge 16: isAdmin = %v", r.Cookie, r.Admin)
+}
+
+func Solve16(random util.Rand) (Result16, error) {
+	key := util.RandBytesFrom(random, util.AesBlockSize)
+
+	encrypt := func(payload []byte) (ciphertext []byte, iv []byte, err error) {
+		if bytes.ContainsAny(payload, ";=") {
+			return nil, nil, fmt.Errorf("payload %q contains forbidden characters", payload)
+		}
+		prefix := []byte("comment1=cooking%20MCs;userdata=")
+		suffix := []byte(";comment2=%20like%20a%20pound%20of%20bacon")
+		plaintext := append(prefix, append(payload, suffix...)...)
+		localIv := util.RandBytesFrom(random, util.AesBlockSize)
+		res, err := util.AesCbcEncrypt(plaintext, key, localIv)
+		return res, localIv, err
+	}
+
+	decrypt := func(ciphertext []byte, iv []byte) (cookie string, isAdmin bool, err error) {
+		decrypted, err := util.AesCbcDecryptWithPadding(ciphertext, key, iv, util.PKCS7)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to decrypt %q: %w", ciphertext, err)
+		}
+		parsed, err := util.ParseKVWithOptions(string(decrypted), util.KVOptions{PairSeparator: ';'})
+		return string(decrypted), err == nil && parsed["admin"] == "true", nil
+	}
+
+	payload := append(
+		bytes.Repeat([]byte("A"), util.AesBlockSize), // We will flip bits in this block...
+		[]byte("?admin?true")...,                     // ...in order to change this one.
+	)
+
+	// The block before the flipped one gets scrambled and may happen to contain a ';' that breaks
+	// the cookie, so retry with a fresh IV in that case.
+	const attempts = 10
+	var res Result16
+	for res.Attempts < attempts && !res.Admin {
+		res.Attempts++
+		ciphertext, iv, err := encrypt(payload)
+		if err != nil {
+			return Result16{}, err
+		}
+
+		bitFlip := func(byteIdx int, bitIdx int) {
+			ciphertext[byteIdx] = ciphertext[byteIdx] ^ (1 << bitIdx)
+		}
+
+		// The 2nd (0-indexed) block contains the block we want to mess with
+		start := 2 * util.AesBlockSize
+
+		//        7 6 5 4 3  2   1  0
+		// '?' is 0 0 1 1 1  1   1  1
+		// ';' is 0 0 1 1 1 [0]  1  1
+		// '=' is 0 0 1 1 1  1  [0] 1
+
+		// The suffix already starts with ';', so only two characters need to change.
+		// v             v
+		// ?admin?true → ;admin?true
+		bitFlip(start, 2)
+		//       v             v
+		// ;admin?true → ;admin=true
+		bitFlip(start+6, 1)
+
+		if res.Cookie, res.Admin, err = decrypt(ciphertext, iv); err != nil {
+			return Result16{}, err
+		}
+	}
+	if !res.Admin {
+		return Result16{}, fmt.Errorf("no admin cookie in %d attempts, the last one is %q", res.Attempts, res.Cookie)
+	}
+	return res, nil
+}
+
+func init() {
+	challenge.Register(2, 9, func(env *challenge.Env) (fmt.Stringer, error) { return Solve9() })
+	challenge.Register(2, 10, func(env *challenge.Env) (fmt.Stringer, error) { return Solve10() })
+	challenge.Register(2, 11, func(env *challenge.Env) (fmt.Stringer, error) { return Solve11(env.Random) })
+	challenge.Register(2, 12, func(env *challenge.Env) (fmt.Stringer, error) { return Solve12(env.Random) })
+	challenge.Register(2, 13, func(env *challenge.Env) (fmt.Stringer, error) { return Solve13(env.Random) })
+	challenge.Register(2, 14, func(env *challenge.Env) (fmt.Stringer, error) { return Solve14(env.Context, env.Random) })
+	challenge.Register(2, 15, func(env *challenge.Env) (fmt.Stringer, error) { return Solve15() })
+	challenge.Register(2, 16, func(env *challenge.Env) (fmt.Stringer, error) { return Solve16(env.Random) })
+}
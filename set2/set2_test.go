@@ -0,0 +1,117 @@
+package set2
+
+import (
+	"context"
+	"cryptopals/util"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// The data files are in the root of the repository.
+func TestMain(m *testing.M) {
+	if err := os.Chdir(".."); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func readSecret(t *testing.T) string {
+	secret, err := util.ReadBase64File("12.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(secret)
+}
+
+func TestSolve9(t *testing.T) {
+	res, err := Solve9()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "YELLOW SUBMARINE\x04\x04\x04\x04"; res.Padded != expected {
+		t.Fatalf("Expected %q, got %q", expected, res.Padded)
+	}
+}
+
+func TestSolve10(t *testing.T) {
+	res, err := Solve10()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "I'm back and I'm ringin' the bell \n"; !strings.HasPrefix(res.Plaintext, expected) {
+		t.Fatalf("Expected a plaintext starting with %q, got %q", expected, res.Plaintext)
+	}
+}
+
+func TestSolve11(t *testing.T) {
+	res, err := Solve11(util.NewSeededRand(11))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Guessed != res.Attempts {
+		t.Fatalf("Expected %v, got %v", res.Attempts, res.Guessed)
+	}
+}
+
+func TestSolve12(t *testing.T) {
+	res, err := Solve12(util.NewSeededRand(12))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := readSecret(t); res.BlockSize != util.AesBlockSize || res.Secret != expected {
+		t.Fatalf("Expected block size %v and %q, got %v and %q", util.AesBlockSize, expected, res.BlockSize, res.Secret)
+	}
+}
+
+func TestSolve13(t *testing.T) {
+	res, err := Solve13(util.NewSeededRand(13))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Admin {
+		t.Fatalf("Expected an admin profile, got %v", res.Profile)
+	}
+}
+
+func TestSolve14(t *testing.T) {
+	if testing.Short() {
+		t.Skip("takes a few seconds")
+	}
+	res, err := Solve14(context.Background(), util.NewSeededRand(14))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := readSecret(t); res.Secret != expected {
+		t.Fatalf("Expected %q, got %q", expected, res.Secret)
+	}
+}
+
+func TestSolve15(t *testing.T) {
+	res, err := Solve15()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Checks) != 3 || !res.Checks[0].Valid || res.Checks[1].Valid || res.Checks[2].Valid {
+		t.Fatalf("Expected only the first of 3 paddings to be valid, got %+v", res.Checks)
+	}
+}
+
+func TestSolve16(t *testing.T) {
+	res, err := Solve16(util.NewSeededRand(16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Admin || !strings.Contains(res.Cookie, ";admin=true;") {
+		t.Fatalf("Expected an admin cookie with %q, got %q", ";admin=true;", res.Cookie)
+	}
+}
+
+func TestSolve14Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Solve14(ctx, util.NewSeededRand(14)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected %v, got %v", context.Canceled, err)
+	}
+}
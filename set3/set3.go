@@ -0,0 +1,221 @@
+package set3
+
+import (
+	"bytes"
+	"context"
+	"cryptopals/challenge"
+	"cryptopals/mt"
+	"cryptopals/util"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var challenge17Strings = []string{
+	"MDAwMDAwTm93IHRoYXQgdGhlIHBhcnR5IGlzIGp1bXBpbmc=",
+	"MDAwMDAxV2l0aCB0aGUgYmFzcyBraWNrZWQgaW4gYW5kIHRoZSBWZWdhJ3MgYXJlIHB1bXBpbic=",
+	"MDAwMDAyUXVpY2sgdG8gdGhlIHBvaW50LCB0byB0aGUgcG9pbnQsIG5vIGZha2luZw==",
+	"MDAwMDAzQ29va2luZyBNQydzIGxpa2UgYSBwb3VuZCBvZiBiYWNvbg==",
+	"MDAwMDA0QnVybmluZyAnZW0sIGlmIHlvdSBhaW4ndCBxdWljayBhbmQgbmltYmxl",
+	"MDAwMDA1SSBnbyBjcmF6eSB3aGVuIEkgaGVhciBhIGN5bWJhbA==",
+	"MDAwMDA2QW5kIGEgaGlnaCBoYXQgd2l0aCBhIHNvdXBlZCB1cCB0ZW1wbw==",
+	"MDAwMDA3SSdtIG9uIGEgcm9sbCwgaXQncyB0aW1lIHRvIGdvIHNvbG8=",
+	"MDAwMDA4b2xsaW4nIGluIG15IGZpdmUgcG9pbnQgb2g=",
+	"MDAwMDA5aXRoIG15IHJhZy10b3AgZG93biBzbyBteSBoYWlyIGNhbiBibG93",
+}
+
+type Result17 struct {
+	Plaintexts []string
+	Queries    int
+}
+
+func (r Result17) String() string {
+	var lines []string
+	for _, p := range r.Plaintexts {
+		lines = append(lines, fmt.Sprintf("Challenge 17: %q", p))
+	}
+	lines = append(lines, fmt.Sprintf("Challenge 17: %d oracle queries", r.Queries))
+	return strings.Join(lines, "\n")
+}
+
+// Solve17 decrypts every string instead of a random one, so that the result is always the same.
+func Solve17(random util.Rand) (Result17, error) {
+	key := util.RandBytesFrom(random, util.AesBlockSize)
+	oracle, err := util.NewAesCbcPaddingOracle(key)
+	if err != nil {
+		return Result17{}, err
+	}
+
+	var res Result17
+	for _, encoded := range challenge17Strings {
+		plaintext, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return Result17{}, err
+		}
+		iv := util.RandBytesFrom(random, util.AesBlockSize)
+		ciphertext, err := util.AesCbcEncrypt(plaintext, key, iv)
+		if err != nil {
+			return Result17{}, err
+		}
+
+		decrypted, queries, err := util.PaddingOracleDecrypt(oracle, iv, ciphertext)
+		if err != nil {
+			return Result17{}, err
+		}
+		res.Plaintexts = append(res.Plaintexts, string(decrypted))
+		res.Queries += queries
+	}
+	return res, nil
+}
+
+type Result21 struct{}
+
+func (r Result21) String() string {
+	return "Challenge 21: is just the implementation of Mersenne Twister in mt/mt.go"
+}
+
+func Solve21() (Result21, error) {
+	return Result21{}, nil
+}
+
+type Result22 struct {
+	Seed  uint32
+	Found uint32
+}
+
+func (r Result22) String() string {
+	return fmt.Sprintf("Challenge 22: original seed = %d, found seed = %d", r.Seed, r.Found)
+}
+
+func Solve22(ctx context.Context, random util.Rand) (Result22, error) {
+	curTime := uint32(time.Now().Unix())
+
+	getDelta := func() uint32 {
+		min, max := 40, 1000
+		return uint32(random.Intn(max-min) + min)
+	}
+
+	seed := curTime + getDelta()
+	rng := mt.New(seed)
+	rngOut := rng.Next()
+
+	userTime := seed + getDelta()
+	for delta := uint32(0); ; delta++ {
+		if err := ctx.Err(); err != nil {
+			return Result22{}, err
+		}
+		candSeed := userTime - delta
+		candMt := mt.New(candSeed)
+		if candMt.Next() == rngOut {
+			if candSeed != seed {
+				return Result22{}, fmt.Errorf("found seed %d instead of %d", candSeed, seed)
+			}
+			return Result22{seed, candSeed}, nil
+		}
+	}
+}
+
+type Result23 struct {
+	Matches int
+	Steps   int
+}
+
+func (r Result23) String() string {
+	return fmt.Sprintf("Challenge 23: the cloned RNG agrees with the real one for %d steps out of %d", r.Matches, r.Steps)
+}
+
+func Solve23(random util.Rand) (Result23, error) {
+	seed := random.Uint32()
+	rng := mt.New(seed)
+	cloned := make([]uint32, mt.N)
+	for i := 0; i < mt.N; i++ {
+		cloned[i] = mt.Untemper(rng.Next())
+	}
+
+	fakeRng := mt.Clone(cloned)
+	res := Result23{Steps: 1000}
+	for ii := 0; ii < res.Steps; ii++ {
+		if rng.Next() == fakeRng.Next() {
+			res.Matches++
+		}
+	}
+	if res.Matches != res.Steps {
+		return Result23{}, fmt.Errorf("the cloned RNG agrees with the real one only for %d steps out of %d", res.Matches, res.Steps)
+	}
+	return res, nil
+}
+
+type Result24 struct {
+	Seed16           uint32
+	RestoredSeed16   uint32
+	TimestampGuesses int
+	Attempts         int
+}
+
+func (r Result24) String() string {
+	return fmt.Sprintf(
+		"Challenge 24: 16-bit seed = %d/%d, timestamp guesses = %d/%d",
+		r.Seed16, r.RestoredSeed16, r.TimestampGuesses, r.Attempts,
+	)
+}
+
+func Solve24(ctx context.Context, random util.Rand) (Result24, error) {
+	knownSuffix := bytes.Repeat([]byte("A"), 14)
+	plaintext := append(util.RandBytesFrom(random, random.Intn(42)), knownSuffix...)
+	res := Result24{Seed16: uint32(random.Intn(1 << 16)), Attempts: 1000}
+	ciphertext16 := mt.Crypt(plaintext, res.Seed16)
+
+	for candSeed := uint32(0); candSeed < (1 << 16); candSeed++ {
+		if err := ctx.Err(); err != nil {
+			return Result24{}, err
+		}
+		if bytes.HasSuffix(mt.Crypt(ciphertext16, candSeed), knownSuffix) {
+			res.RestoredSeed16 = candSeed
+			break
+		}
+	}
+
+	for attempts := 0; attempts < res.Attempts; attempts++ {
+		if err := ctx.Err(); err != nil {
+			return Result24{}, err
+		}
+		useTime := random.Intn(2) == 1
+		var seed uint32
+		if useTime {
+			seed = uint32(time.Now().Unix())
+		} else {
+			seed = random.Uint32()
+		}
+		ciphertextTime := mt.Crypt(plaintext, seed)
+
+		guessedUseTime := false
+		curTime := uint32(time.Now().Unix())
+		for delta := uint32(0); delta < 60; delta++ {
+			candSeed := curTime - delta
+			if bytes.HasSuffix(mt.Crypt(ciphertextTime, candSeed), knownSuffix) {
+				guessedUseTime = true
+				break
+			}
+		}
+
+		if useTime == guessedUseTime {
+			res.TimestampGuesses++
+		}
+	}
+	if res.RestoredSeed16 != res.Seed16 {
+		return Result24{}, fmt.Errorf("restored the 16-bit seed %d instead of %d", res.RestoredSeed16, res.Seed16)
+	}
+	if res.TimestampGuesses != res.Attempts {
+		return Result24{}, fmt.Errorf("told a timestamp seed apart only %d times out of %d", res.TimestampGuesses, res.Attempts)
+	}
+	return res, nil
+}
+
+func init() {
+	challenge.Register(3, 17, func(env *challenge.Env) (fmt.Stringer, error) { return Solve17(env.Random) })
+	challenge.Register(3, 21, func(env *challenge.Env) (fmt.Stringer, error) { return Solve21() })
+	challenge.Register(3, 22, func(env *challenge.Env) (fmt.Stringer, error) { return Solve22(env.Context, env.Random) })
+	challenge.Register(3, 23, func(env *challenge.Env) (fmt.Stringer, error) { return Solve23(env.Random) })
+	challenge.Register(3, 24, func(env *challenge.Env) (fmt.Stringer, error) { return Solve24(env.Context, env.Random) })
+}
@@ -0,0 +1,60 @@
+package set3
+
+import (
+	"context"
+	"cryptopals/util"
+	"errors"
+	"testing"
+)
+
+func TestSolve17(t *testing.T) {
+	res, err := Solve17(util.NewSeededRand(17))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Plaintexts) != 10 {
+		t.Fatalf("Expected %v plaintexts, got %v", 10, len(res.Plaintexts))
+	}
+	first, last := "000000Now that the party is jumping", "000009ith my rag-top down so my hair can blow"
+	if res.Plaintexts[0] != first || res.Plaintexts[9] != last {
+		t.Fatalf("Expected %q to %q, got %q", first, last, res.Plaintexts)
+	}
+}
+
+func TestSolve22(t *testing.T) {
+	res, err := Solve22(context.Background(), util.NewSeededRand(22))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Found != res.Seed {
+		t.Fatalf("Expected %v, got %v", res.Seed, res.Found)
+	}
+}
+
+func TestSolve23(t *testing.T) {
+	res, err := Solve23(util.NewSeededRand(23))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Matches != res.Steps {
+		t.Fatalf("Expected %v, got %v", res.Steps, res.Matches)
+	}
+}
+
+func TestSolve24(t *testing.T) {
+	res, err := Solve24(context.Background(), util.NewSeededRand(24))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.RestoredSeed16 != res.Seed16 || res.TimestampGuesses != res.Attempts {
+		t.Fatalf("Expected seed %v and %v timestamp guesses, got %v and %v", res.Seed16, res.Attempts, res.RestoredSeed16, res.TimestampGuesses)
+	}
+}
+
+func TestSolve24Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Solve24(ctx, util.NewSeededRand(24)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected %v, got %v", context.Canceled, err)
+	}
+}
@@ -0,0 +1,78 @@
+package util
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+)
+
+// Aes expands the key once, so that oracles called in a tight loop don't pay for it on
+// every call. All methods append their output to dst and return the extended slice,
+// so a buffer can be reused between calls with dst[:0].
+type Aes struct {
+	block cipher.Block
+}
+
+func NewAes(key []byte) (*Aes, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return &Aes{block}, nil
+}
+
+func appendPKCS7(dst []byte, input []byte, blockSize int) []byte {
+	rem := blockSize - len(input)%blockSize
+	dst = append(dst, input...)
+	for i := 0; i < rem; i++ {
+		dst = append(dst, byte(rem))
+	}
+	return dst
+}
+
+func (a *Aes) EcbEncrypt(dst []byte, plaintext []byte) []byte {
+	start := len(dst)
+	dst = appendPKCS7(dst, plaintext, AesBlockSize)
+	ecbEncrypter{a.block}.CryptBlocks(dst[start:], dst[start:])
+	return dst
+}
+
+func (a *Aes) EcbDecrypt(dst []byte, ciphertext []byte) ([]byte, error) {
+	if err := checkLength(ciphertext, AesBlockSize); err != nil {
+		return dst, err
+	}
+
+	start := len(dst)
+	dst = append(dst, ciphertext...)
+	ecbDecrypter{a.block}.CryptBlocks(dst[start:], dst[start:])
+	unpadded, err := PKCS7Unpad(dst[start:], AesBlockSize)
+	if err != nil {
+		return dst[:start], err
+	}
+	return dst[:start+len(unpadded)], nil
+}
+
+func (a *Aes) CbcEncrypt(dst []byte, plaintext []byte, iv []byte) ([]byte, error) {
+	if err := checkIV(iv, AesBlockSize); err != nil {
+		return dst, err
+	}
+
+	start := len(dst)
+	dst = appendPKCS7(dst, plaintext, AesBlockSize)
+	newCbcEncrypter(a.block, iv).CryptBlocks(dst[start:], dst[start:])
+	return dst, nil
+}
+
+// CbcDecrypt leaves the padding in place, just like AesCbcDecrypt.
+func (a *Aes) CbcDecrypt(dst []byte, ciphertext []byte, iv []byte) ([]byte, error) {
+	if err := checkIV(iv, AesBlockSize); err != nil {
+		return dst, err
+	}
+	if err := checkLength(ciphertext, AesBlockSize); err != nil {
+		return dst, err
+	}
+
+	start := len(dst)
+	dst = append(dst, ciphertext...)
+	newCbcDecrypter(a.block, iv).CryptBlocks(dst[start:], dst[start:])
+	return dst, nil
+}
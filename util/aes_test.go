@@ -0,0 +1,79 @@
+package util
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAesMatchesOneShot(t *testing.T) {
+	key := []byte("YELLOW SUBMARINE")
+	iv := []byte("0123456789abcdef")
+	a, err := NewAes(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := []byte("prefix")
+	for _, size := range []int{0, 1, 16, 33} {
+		plaintext := bytes.Repeat([]byte("Z"), size)
+
+		expected, _ := AesEcbEncrypt(plaintext, key)
+		buf = a.EcbEncrypt(buf[:6], plaintext)
+		if string(buf[:6]) != "prefix" || !bytes.Equal(buf[6:], expected) {
+			t.Fatalf("ECB, %d bytes: expected %x, got %x", size, expected, buf[6:])
+		}
+		buf, err = a.EcbDecrypt(buf[:6], expected)
+		if err != nil || !bytes.Equal(buf[6:], plaintext) {
+			t.Fatalf("ECB, %d bytes: expected %q, got %q (%v)", size, plaintext, buf[6:], err)
+		}
+
+		expected, _ = AesCbcEncrypt(plaintext, key, iv)
+		buf, err = a.CbcEncrypt(buf[:6], plaintext, iv)
+		if err != nil || !bytes.Equal(buf[6:], expected) {
+			t.Fatalf("CBC, %d bytes: expected %x, got %x (%v)", size, expected, buf[6:], err)
+		}
+		expectedPadded, _ := AesCbcDecrypt(expected, key, iv)
+		buf, err = a.CbcDecrypt(buf[:6], expected, iv)
+		if err != nil || !bytes.Equal(buf[6:], expectedPadded) {
+			t.Fatalf("CBC, %d bytes: expected %q, got %q (%v)", size, expectedPadded, buf[6:], err)
+		}
+	}
+}
+
+var benchPlaintext = bytes.Repeat([]byte("A"), 200)
+
+func BenchmarkAesEcbEncrypt(b *testing.B) {
+	key := []byte("YELLOW SUBMARINE")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		AesEcbEncrypt(benchPlaintext, key)
+	}
+}
+
+func BenchmarkAesEcbEncryptKeyed(b *testing.B) {
+	a, _ := NewAes([]byte("YELLOW SUBMARINE"))
+	var buf []byte
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = a.EcbEncrypt(buf[:0], benchPlaintext)
+	}
+}
+
+func BenchmarkAesCbcEncrypt(b *testing.B) {
+	key := []byte("YELLOW SUBMARINE")
+	iv := make([]byte, AesBlockSize)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		AesCbcEncrypt(benchPlaintext, key, iv)
+	}
+}
+
+func BenchmarkAesCbcEncryptKeyed(b *testing.B) {
+	a, _ := NewAes([]byte("YELLOW SUBMARINE"))
+	iv := make([]byte, AesBlockSize)
+	var buf []byte
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf, _ = a.CbcEncrypt(buf[:0], benchPlaintext, iv)
+	}
+}
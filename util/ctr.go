@@ -0,0 +1,108 @@
+package util
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+)
+
+// CtrLayout describes where the counter lives inside a counter block.
+// The bytes not covered by the counter are filled with the nonce, in order.
+type CtrLayout struct {
+	CounterOffset int
+	CounterSize   int
+	BigEndian     bool
+	Initial       uint64
+}
+
+var (
+	// 64-bit nonce followed by a 64-bit little-endian counter, as in challenge 18.
+	CtrCryptopals = CtrLayout{CounterOffset: 8, CounterSize: 8}
+	// 64-bit nonce followed by a 64-bit big-endian counter, as in SP 800-38A.
+	CtrNist = CtrLayout{CounterOffset: 8, CounterSize: 8, BigEndian: true}
+)
+
+type ctrStream struct {
+	block     cipher.Block
+	layout    CtrLayout
+	counter   []byte
+	keystream []byte
+	used      int
+}
+
+func NewCtr(block cipher.Block, nonce []byte, layout CtrLayout) (cipher.Stream, error) {
+	blockSize := block.BlockSize()
+	if layout.CounterSize <= 0 || layout.CounterOffset < 0 || layout.CounterOffset+layout.CounterSize > blockSize {
+		return nil, fmt.Errorf("counter at [%d, %d) does not fit into a %d-byte block", layout.CounterOffset, layout.CounterOffset+layout.CounterSize, blockSize)
+	}
+	if len(nonce) != blockSize-layout.CounterSize {
+		return nil, fmt.Errorf("%w: nonce had %d bytes, expected %d", ErrBadIV, len(nonce), blockSize-layout.CounterSize)
+	}
+
+	res := &ctrStream{
+		block:     block,
+		layout:    layout,
+		counter:   make([]byte, blockSize),
+		keystream: make([]byte, blockSize),
+		used:      blockSize,
+	}
+	copy(res.counter, nonce[:layout.CounterOffset])
+	copy(res.counter[layout.CounterOffset+layout.CounterSize:], nonce[layout.CounterOffset:])
+	initial := layout.Initial
+	for i := 0; i < layout.CounterSize && i < 8; i++ {
+		res.counter[res.counterPos(i)] = byte(initial)
+		initial >>= 8
+	}
+	return res, nil
+}
+
+// counterPos maps the i-th least significant counter byte to its position in the block.
+func (s *ctrStream) counterPos(i int) int {
+	if s.layout.BigEndian {
+		return s.layout.CounterOffset + s.layout.CounterSize - 1 - i
+	}
+	return s.layout.CounterOffset + i
+}
+
+func (s *ctrStream) increment() {
+	for i := 0; i < s.layout.CounterSize; i++ {
+		pos := s.counterPos(i)
+		s.counter[pos]++
+		if s.counter[pos] != 0 {
+			break
+		}
+	}
+}
+
+func (s *ctrStream) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("util: output smaller than input")
+	}
+	for i := range src {
+		if s.used == len(s.keystream) {
+			s.block.Encrypt(s.keystream, s.counter)
+			s.increment()
+			s.used = 0
+		}
+		dst[i] = src[i] ^ s.keystream[s.used]
+		s.used++
+	}
+}
+
+func NewAesCtr(key []byte, nonce []byte, layout CtrLayout) (cipher.Stream, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return NewCtr(block, nonce, layout)
+}
+
+func AesCtrCrypt(input []byte, key []byte, nonce []byte, layout CtrLayout) ([]byte, error) {
+	stream, err := NewAesCtr(key, nonce, layout)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]byte, len(input))
+	stream.XORKeyStream(res, input)
+	return res, nil
+}
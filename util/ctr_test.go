@@ -0,0 +1,84 @@
+package util
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+)
+
+func TestAesCtrCrypt(t *testing.T) {
+	ciphertext, _ := base64.StdEncoding.DecodeString("L77na/nrFsKvynd6HzOoG7GHTLXsTVu9qvY/2syLXzhPweyyMTJULu/6/kXX0KSvoOLSFQ==")
+	expected := "Yo, VIP Let's kick it Ice, Ice, baby Ice, Ice, baby "
+	actual, err := AesCtrCrypt(ciphertext, []byte("YELLOW SUBMARINE"), make([]byte, 8), CtrCryptopals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(actual) != expected {
+		t.Fatalf("Expected %q, got %q", expected, actual)
+	}
+
+	// SP 800-38A, F.5.1 (CTR-AES128.Encrypt), first two blocks.
+	key, _ := hex.DecodeString("2b7e151628aed2a6abf7158809cf4f3c")
+	plaintext, _ := hex.DecodeString("6bc1bee22e409f96e93d7e117393172aae2d8a571e03ac9c9eb76fac45af8e51")
+	expectedCiphertext, _ := hex.DecodeString("874d6191b620e3261bef6864990db6ce9806f66b7970fdff8617187bb9fffdff")
+	nist := CtrNist
+	nist.Initial = 0xf8f9fafbfcfdfeff
+	actual, err = AesCtrCrypt(plaintext, key, []byte{0xf0, 0xf1, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7}, nist)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(actual, expectedCiphertext) {
+		t.Fatalf("Expected %x, got %x", expectedCiphertext, actual)
+	}
+}
+
+func TestCtrMatchesStdlib(t *testing.T) {
+	key := []byte("YELLOW SUBMARINE")
+	block, _ := aes.NewCipher(key)
+	payload := bytes.Repeat([]byte("0123456789"), 20)
+
+	// A 120-bit big-endian counter with an all-ones low half exercises the carry.
+	iv := make([]byte, aes.BlockSize)
+	iv[0] = 0x42
+	copy(iv[8:], bytes.Repeat([]byte{0xff}, 8))
+	expected := make([]byte, len(payload))
+	cipher.NewCTR(block, iv).XORKeyStream(expected, payload)
+
+	layout := CtrLayout{CounterOffset: 1, CounterSize: 15, BigEndian: true, Initial: 0xffffffffffffffff}
+	stream, err := NewCtr(block, []byte{0x42}, layout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := make([]byte, len(payload))
+	// Feed the stream in uneven chunks to check that the keystream position is kept.
+	for i, step := 0, 1; i < len(payload); i, step = i+step, step+3 {
+		end := i + step
+		if end > len(payload) {
+			end = len(payload)
+		}
+		stream.XORKeyStream(actual[i:end], payload[i:end])
+	}
+	if !bytes.Equal(actual, expected) {
+		t.Fatalf("Expected %x, got %x", expected, actual)
+	}
+}
+
+func TestCtrBadLayout(t *testing.T) {
+	block, _ := aes.NewCipher([]byte("YELLOW SUBMARINE"))
+	badTests := []struct {
+		nonce  []byte
+		layout CtrLayout
+	}{
+		{make([]byte, 8), CtrLayout{CounterOffset: 10, CounterSize: 8}},
+		{make([]byte, 16), CtrLayout{}},
+		{make([]byte, 4), CtrCryptopals},
+	}
+	for _, tt := range badTests {
+		if _, err := NewCtr(block, tt.nonce, tt.layout); err == nil {
+			t.Fatalf("Expected an error for %+v", tt.layout)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package util
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+)
+
+// CtsVariant selects one of the ciphertext stealing variants from the SP 800-38A addendum.
+// They only differ in the order of the last two ciphertext blocks.
+type CtsVariant int
+
+const (
+	// The partial next-to-last block goes first.
+	CS1 CtsVariant = iota + 1
+	// Same as CS1 when the plaintext is block-aligned, same as CS3 otherwise.
+	CS2
+	// The last two blocks are always swapped, as in Kerberos (RFC 3962).
+	CS3
+)
+
+func (v CtsVariant) check() error {
+	if v < CS1 || v > CS3 {
+		return fmt.Errorf("unknown ciphertext stealing variant %d", v)
+	}
+	return nil
+}
+
+func (v CtsVariant) swapped(partialLen int, blockSize int) bool {
+	return v == CS3 || v == CS2 && partialLen != blockSize
+}
+
+func CbcCtsEncrypt(block cipher.Block, plaintext []byte, iv []byte, variant CtsVariant) ([]byte, error) {
+	if err := variant.check(); err != nil {
+		return nil, err
+	}
+	bs := block.BlockSize()
+	if len(plaintext) < bs {
+		return nil, fmt.Errorf("%w: ciphertext stealing needs at least %d bytes, got %d", ErrBadLength, bs, len(plaintext))
+	}
+	res, err := CbcEncryptWithPadding(block, plaintext, iv, ZeroPadding)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == bs {
+		return res, nil
+	}
+
+	// The last block is full, the next-to-last one is truncated to the length of the last plaintext block.
+	d := len(plaintext) - (len(res) - bs)
+	swapped := variant.swapped(d, bs)
+	tail := make([]byte, 0, bs+d)
+	prev, last := res[len(res)-2*bs:len(res)-2*bs+d], res[len(res)-bs:]
+	if swapped {
+		tail = append(append(tail, last...), prev...)
+	} else {
+		tail = append(append(tail, prev...), last...)
+	}
+	return append(res[:len(res)-2*bs], tail...), nil
+}
+
+func CbcCtsDecrypt(block cipher.Block, ciphertext []byte, iv []byte, variant CtsVariant) ([]byte, error) {
+	if err := variant.check(); err != nil {
+		return nil, err
+	}
+	bs := block.BlockSize()
+	if len(ciphertext) < bs {
+		return nil, fmt.Errorf("%w: ciphertext stealing needs at least %d bytes, got %d", ErrBadLength, bs, len(ciphertext))
+	}
+	if len(ciphertext) == bs {
+		return CbcDecrypt(block, ciphertext, iv)
+	}
+
+	n := (len(ciphertext) + bs - 1) / bs
+	d := len(ciphertext) - (n-1)*bs
+	swapped := variant.swapped(d, bs)
+	tailStart := (n - 2) * bs
+	var prev, last []byte
+	if swapped {
+		last, prev = ciphertext[tailStart:tailStart+bs], ciphertext[tailStart+bs:]
+	} else {
+		prev, last = ciphertext[tailStart:tailStart+d], ciphertext[tailStart+d:]
+	}
+
+	// Decrypting the last block gives the zero-padded plaintext XORed with the full
+	// next-to-last block, so its tail is exactly the part of that block that was stolen.
+	full := make([]byte, n*bs)
+	copy(full, ciphertext[:tailStart])
+	block.Decrypt(full[tailStart:tailStart+bs], last)
+	copy(full[tailStart:], prev)
+	copy(full[tailStart+bs:], last)
+
+	res, err := CbcDecrypt(block, full, iv)
+	if err != nil {
+		return nil, err
+	}
+	return res[:len(ciphertext)], nil
+}
+
+func AesCbcCtsEncrypt(plaintext []byte, key []byte, iv []byte, variant CtsVariant) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return CbcCtsEncrypt(block, plaintext, iv, variant)
+}
+
+func AesCbcCtsDecrypt(ciphertext []byte, key []byte, iv []byte, variant CtsVariant) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return CbcCtsDecrypt(block, ciphertext, iv, variant)
+}
@@ -0,0 +1,62 @@
+package util
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestCbcCts(t *testing.T) {
+	// The addendum to SP 800-38A does not come with test vectors, so these are the
+	// CBC-CS3 vectors from RFC 3962, appendix B. CS1 and CS2 are derived from them by
+	// putting the last two blocks back in the order each variant expects.
+	key := []byte("chicken teriyaki")
+	iv := make([]byte, AesBlockSize)
+	plaintext := []byte("I would like the General Gau's Chicken, please, and wonton soup.")
+	tests := []struct {
+		length int
+		cs3    string
+	}{
+		{17, "c6353568f2bf8cb4d8a580362da7ff7f97"},
+		{31, "fc00783e0efdb2c1d445d4c8eff7ed2297687268d6ecccc0c07b25e25ecfe5"},
+		{32, "39312523a78662d5be7fcbcc98ebf5a897687268d6ecccc0c07b25e25ecfe584"},
+		{47, "97687268d6ecccc0c07b25e25ecfe584b3fffd940c16a18c1b5549d2f838029e39312523a78662d5be7fcbcc98ebf5"},
+		{48, "97687268d6ecccc0c07b25e25ecfe5849dad8bbb96c4cdc03bc103e1a194bbd839312523a78662d5be7fcbcc98ebf5a8"},
+		{64, "97687268d6ecccc0c07b25e25ecfe58439312523a78662d5be7fcbcc98ebf5a84807efe836ee89a526730dbc2f7bc8409dad8bbb96c4cdc03bc103e1a194bbd8"},
+	}
+	for _, tt := range tests {
+		cs3, _ := hex.DecodeString(tt.cs3)
+		tailStart := (len(cs3)-1)/AesBlockSize*AesBlockSize - AesBlockSize
+		d := len(cs3) - tailStart - AesBlockSize
+		cs1 := append(append(append([]byte{}, cs3[:tailStart]...), cs3[tailStart+AesBlockSize:]...), cs3[tailStart:tailStart+AesBlockSize]...)
+		cs2 := cs3
+		if d == AesBlockSize {
+			cs2 = cs1
+		}
+
+		for _, vt := range []struct {
+			variant  CtsVariant
+			expected []byte
+		}{{CS1, cs1}, {CS2, cs2}, {CS3, cs3}} {
+			actual, err := AesCbcCtsEncrypt(plaintext[:tt.length], key, iv, vt.variant)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(actual, vt.expected) {
+				t.Fatalf("CS%d, %d bytes: expected %x, got %x", vt.variant, tt.length, vt.expected, actual)
+			}
+			decrypted, err := AesCbcCtsDecrypt(actual, key, iv, vt.variant)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(decrypted, plaintext[:tt.length]) {
+				t.Fatalf("CS%d, %d bytes: expected %q, got %q", vt.variant, tt.length, plaintext[:tt.length], decrypted)
+			}
+		}
+	}
+
+	if _, err := AesCbcCtsEncrypt(plaintext[:15], key, iv, CS1); !errors.Is(err, ErrBadLength) {
+		t.Fatalf("Expected ErrBadLength, got %v", err)
+	}
+}
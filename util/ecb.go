@@ -0,0 +1,104 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// EcbScore describes the repeated blocks of a ciphertext at the alignment with the most of them.
+// Anything with Repeats > 0 is most likely ECB, since blocks of other modes almost never repeat.
+type EcbScore struct {
+	Offset int
+	// Repeats is the number of blocks equal to some earlier block.
+	Repeats int
+	// LongestRun is the largest number of consecutive equal blocks.
+	LongestRun int
+}
+
+func (s EcbScore) IsEcb() bool {
+	return s.Repeats > 0
+}
+
+func (s EcbScore) better(other EcbScore) bool {
+	if s.Repeats != other.Repeats {
+		return s.Repeats > other.Repeats
+	}
+	return s.LongestRun > other.LongestRun
+}
+
+// HasRepeatedBlock is the allocation-free check for oracles called in a tight loop. It only
+// looks at the aligned blocks, and compares every pair, so it is meant for short ciphertexts.
+func HasRepeatedBlock(ciphertext []byte, blockSize int) bool {
+	for i := 0; i+2*blockSize <= len(ciphertext); i += blockSize {
+		for j := i + blockSize; j+blockSize <= len(ciphertext); j += blockSize {
+			if bytes.Equal(ciphertext[i:i+blockSize], ciphertext[j:j+blockSize]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ScoreEcbAt only looks at the blocks starting at offset, for callers that know the alignment.
+func ScoreEcbAt(ciphertext []byte, blockSize int, offset int) EcbScore {
+	res := EcbScore{Offset: offset}
+	// Slicing a single copy doesn't allocate a string for every block.
+	data := string(ciphertext)
+	seen := make(map[string]bool)
+	run := 0
+	for i := offset; i+blockSize <= len(data); i += blockSize {
+		block := data[i : i+blockSize]
+		if seen[block] {
+			res.Repeats++
+		}
+		seen[block] = true
+
+		if i > offset && block == data[i-blockSize:i] {
+			run++
+		} else {
+			run = 1
+		}
+		if run > res.LongestRun {
+			res.LongestRun = run
+		}
+	}
+	return res
+}
+
+// ScoreEcb tries every alignment, so that ECB data after a header that is not a multiple
+// of the block size is still detected.
+func ScoreEcb(ciphertext []byte, blockSize int) (EcbScore, error) {
+	if blockSize < 1 {
+		return EcbScore{}, fmt.Errorf("%w: %d", ErrBadBlockSize, blockSize)
+	}
+	res := ScoreEcbAt(ciphertext, blockSize, 0)
+	for offset := 1; offset < blockSize; offset++ {
+		if s := ScoreEcbAt(ciphertext, blockSize, offset); s.better(res) {
+			res = s
+		}
+	}
+	return res, nil
+}
+
+type RankedEcb struct {
+	// Index of the ciphertext in the batch.
+	Index int
+	EcbScore
+}
+
+// RankEcb scores every ciphertext and returns them most ECB-like first.
+func RankEcb(ciphertexts [][]byte, blockSize int) ([]RankedEcb, error) {
+	res := make([]RankedEcb, len(ciphertexts))
+	for i, ciphertext := range ciphertexts {
+		s, err := ScoreEcb(ciphertext, blockSize)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = RankedEcb{i, s}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].better(res[j].EcbScore)
+	})
+	return res, nil
+}
@@ -0,0 +1,105 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestScoreEcb(t *testing.T) {
+	key := []byte("YELLOW SUBMARINE")
+	plaintext := bytes.Repeat([]byte("A"), 4*AesBlockSize)
+	ecb, err := AesEcbEncrypt(plaintext, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := ScoreEcb(ecb, AesBlockSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (EcbScore{Offset: 0, Repeats: 3, LongestRun: 4}); s != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, s)
+	}
+
+	// A 5-byte header shifts all blocks.
+	header, err := AesEcbEncrypt([]byte("YELLOW SUBMARINEyellow submarineYELLOW SUBMARINE"), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shifted := append([]byte("HDR: "), header...)
+	s, err = ScoreEcb(shifted, AesBlockSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (EcbScore{Offset: 5, Repeats: 1, LongestRun: 1}); s != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, s)
+	}
+	if s := ScoreEcbAt(shifted, AesBlockSize, 0); s.IsEcb() {
+		t.Fatalf("Expected no repeats at offset 0, got %+v", s)
+	}
+
+	cbc, err := AesCbcEncrypt(plaintext, key, make([]byte, AesBlockSize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := ScoreEcb(cbc, AesBlockSize); s.IsEcb() {
+		t.Fatalf("Expected no repeats for CBC, got %+v", s)
+	}
+
+	if _, err := ScoreEcb(ecb, 0); !errors.Is(err, ErrBadBlockSize) {
+		t.Fatalf("Expected %v, got %v", ErrBadBlockSize, err)
+	}
+}
+
+func TestScoreEcbBlockSize(t *testing.T) {
+	data := []byte("xyzabcdefgabcdefgabcdefgXY")
+	s, err := ScoreEcb(data, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (EcbScore{Offset: 3, Repeats: 2, LongestRun: 3}); s != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, s)
+	}
+}
+
+func TestRankEcb(t *testing.T) {
+	ciphertexts := [][]byte{
+		[]byte("0123456789abcdefghijklmnopqrstuv"),
+		[]byte("AAAABBBBAAAACCCC"),
+		[]byte("AAAAAAAAAAAAAAAA"),
+	}
+	ranked, err := RankEcb(ciphertexts, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ranked[0].Index != 2 || ranked[1].Index != 1 || ranked[2].Index != 0 {
+		t.Fatalf("Expected indices 2, 1, 0, got %+v", ranked)
+	}
+	if ranked[2].IsEcb() {
+		t.Fatalf("Expected no repeats in %q, got %+v", ciphertexts[0], ranked[2])
+	}
+}
+
+func TestHasRepeatedBlock(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected bool
+	}{
+		{"", false},
+		{"abcd", false},
+		{"abcdabcd", true},
+		{"abcdefghabcd", true},
+		{"abcdefghijkl", false},
+		// Repeats that are not aligned don't count.
+		{"xabcdabcdyyy", false},
+	}
+	for _, tt := range tests {
+		if actual := HasRepeatedBlock([]byte(tt.data), 4); actual != tt.expected {
+			t.Fatalf("Expected %v for %q, got %v", tt.expected, tt.data, actual)
+		}
+	}
+	data := []byte("abcdefghabcdijkl")
+	if allocs := testing.AllocsPerRun(100, func() { HasRepeatedBlock(data, 4) }); allocs != 0 {
+		t.Fatalf("Expected no allocations, got %v", allocs)
+	}
+}
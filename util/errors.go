@@ -0,0 +1,55 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrBadBlockSize    = errors.New("invalid block size")
+	ErrBadLength       = errors.New("invalid input length")
+	ErrBadPaddingValue = errors.New("invalid padding value")
+	ErrBadPaddingBytes = errors.New("invalid padding bytes")
+	ErrBadIV           = errors.New("invalid IV")
+	ErrAuthFailed      = errors.New("message authentication failed")
+	ErrBadOracle       = errors.New("inconsistent padding oracle")
+)
+
+// PaddingError describes why a padded message was rejected. Err is either
+// ErrBadPaddingValue or ErrBadPaddingBytes, so callers can match on the class with errors.Is.
+type PaddingError struct {
+	Err error
+	// Position counts from the end of the message, starting at 1 for the last byte.
+	Position int
+	Got      byte
+	Expected byte
+}
+
+func (e *PaddingError) Error() string {
+	return fmt.Sprintf("%v at position %d: got %d, expected %d", e.Err, e.Position, e.Got, e.Expected)
+}
+
+func (e *PaddingError) Unwrap() error {
+	return e.Err
+}
+
+func checkBlockSize(blockSize int) error {
+	if blockSize < 1 || blockSize > 255 {
+		return fmt.Errorf("%w: %d", ErrBadBlockSize, blockSize)
+	}
+	return nil
+}
+
+func checkLength(input []byte, blockSize int) error {
+	if len(input)%blockSize != 0 {
+		return fmt.Errorf("%w for block size %d: %d", ErrBadLength, blockSize, len(input))
+	}
+	return nil
+}
+
+func checkIV(iv []byte, blockSize int) error {
+	if len(iv) != blockSize {
+		return fmt.Errorf("%w: had %d bytes, expected %d", ErrBadIV, len(iv), blockSize)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package util
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+)
+
+type ofbStream struct {
+	block     cipher.Block
+	keystream []byte
+	used      int
+}
+
+func NewOfb(block cipher.Block, iv []byte) (cipher.Stream, error) {
+	if err := checkIV(iv, block.BlockSize()); err != nil {
+		return nil, err
+	}
+	return &ofbStream{block: block, keystream: append([]byte(nil), iv...), used: len(iv)}, nil
+}
+
+func (s *ofbStream) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("util: output smaller than input")
+	}
+	for i := range src {
+		if s.used == len(s.keystream) {
+			s.block.Encrypt(s.keystream, s.keystream)
+			s.used = 0
+		}
+		dst[i] = src[i] ^ s.keystream[s.used]
+		s.used++
+	}
+}
+
+func OfbCrypt(block cipher.Block, input []byte, iv []byte) ([]byte, error) {
+	stream, err := NewOfb(block, iv)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]byte, len(input))
+	stream.XORKeyStream(res, input)
+	return res, nil
+}
+
+// cfb implements CFB with a segment size of segmentSize bytes: CFB-8 for 1, full-block CFB for the block size.
+// The last segment of the input may be shorter than the others.
+func cfb(block cipher.Block, input []byte, iv []byte, segmentSize int, decrypt bool) ([]byte, error) {
+	bs := block.BlockSize()
+	if err := checkIV(iv, bs); err != nil {
+		return nil, err
+	}
+	if segmentSize < 1 || segmentSize > bs {
+		return nil, fmt.Errorf("CFB segment size must be between 1 and %d bytes, got %d", bs, segmentSize)
+	}
+
+	res := make([]byte, len(input))
+	register := append([]byte(nil), iv...)
+	out := make([]byte, bs)
+	for i := 0; i < len(input); i += segmentSize {
+		end := i + segmentSize
+		if end > len(input) {
+			end = len(input)
+		}
+		block.Encrypt(out, register)
+		for j := i; j < end; j++ {
+			res[j] = input[j] ^ out[j-i]
+		}
+
+		ciphertext := res[i:end]
+		if decrypt {
+			ciphertext = input[i:end]
+		}
+		copy(register, register[segmentSize:])
+		copy(register[bs-segmentSize:], ciphertext)
+	}
+	return res, nil
+}
+
+func CfbEncrypt(block cipher.Block, plaintext []byte, iv []byte, segmentSize int) ([]byte, error) {
+	return cfb(block, plaintext, iv, segmentSize, false)
+}
+
+func CfbDecrypt(block cipher.Block, ciphertext []byte, iv []byte, segmentSize int) ([]byte, error) {
+	return cfb(block, ciphertext, iv, segmentSize, true)
+}
+
+func AesOfbCrypt(input []byte, key []byte, iv []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return OfbCrypt(block, input, iv)
+}
+
+func AesCfb8Encrypt(plaintext []byte, key []byte, iv []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return CfbEncrypt(block, plaintext, iv, 1)
+}
+
+func AesCfb8Decrypt(ciphertext []byte, key []byte, iv []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return CfbDecrypt(block, ciphertext, iv, 1)
+}
+
+func AesCfbEncrypt(plaintext []byte, key []byte, iv []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return CfbEncrypt(block, plaintext, iv, AesBlockSize)
+}
+
+func AesCfbDecrypt(ciphertext []byte, key []byte, iv []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return CfbDecrypt(block, ciphertext, iv, AesBlockSize)
+}
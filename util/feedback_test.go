@@ -0,0 +1,61 @@
+package util
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+)
+
+func TestFeedbackModes(t *testing.T) {
+	// SP 800-38A, F.3.7, F.3.13 and F.4.1.
+	key, _ := hex.DecodeString("2b7e151628aed2a6abf7158809cf4f3c")
+	iv, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	plaintext, _ := hex.DecodeString("6bc1bee22e409f96e93d7e117393172aae2d8a571e03ac9c9eb76fac45af8e51")
+	tests := []struct {
+		name     string
+		encrypt  func([]byte, []byte, []byte) ([]byte, error)
+		decrypt  func([]byte, []byte, []byte) ([]byte, error)
+		length   int
+		expected string
+	}{
+		{"CFB-8", AesCfb8Encrypt, AesCfb8Decrypt, 18, "3b79424c9c0dd436bace9e0ed4586a4f32b9"},
+		{"CFB-128", AesCfbEncrypt, AesCfbDecrypt, 32, "3b3fd92eb72dad20333449f8e83cfb4ac8a64537a0b3a93fcde3cdad9f1ce58b"},
+		{"CFB-128", AesCfbEncrypt, AesCfbDecrypt, 20, "3b3fd92eb72dad20333449f8e83cfb4ac8a64537"},
+		{"OFB", AesOfbCrypt, AesOfbCrypt, 32, "3b3fd92eb72dad20333449f8e83cfb4a7789508d16918f03f53c52dac54ed825"},
+	}
+	for _, tt := range tests {
+		expected, _ := hex.DecodeString(tt.expected)
+		actual, err := tt.encrypt(plaintext[:tt.length], key, iv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(actual, expected) {
+			t.Fatalf("%s: expected %x, got %x", tt.name, expected, actual)
+		}
+		decrypted, err := tt.decrypt(actual, key, iv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decrypted, plaintext[:tt.length]) {
+			t.Fatalf("%s: expected %x, got %x", tt.name, plaintext[:tt.length], decrypted)
+		}
+	}
+}
+
+func TestCfb8ZeroIV(t *testing.T) {
+	// With an all-zero IV, roughly one key in 256 maps an all-zero plaintext to an all-zero ciphertext.
+	zeros := make([]byte, 8)
+	key := make([]byte, AesBlockSize)
+	for i := uint32(0); i < 10000; i++ {
+		binary.LittleEndian.PutUint32(key, i)
+		encrypted, err := AesCfb8Encrypt(zeros, key, make([]byte, AesBlockSize))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Equal(encrypted, zeros) {
+			return
+		}
+	}
+	t.Fatalf("No key produced an all-zero ciphertext")
+}
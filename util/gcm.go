@@ -0,0 +1,216 @@
+package util
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+)
+
+// GfElement is an element of GF(2^128) in the bit order used by GCM:
+// the most significant bit of the first byte is the coefficient of x^0.
+type GfElement [16]byte
+
+func (x GfElement) halves() (uint64, uint64) {
+	return binary.BigEndian.Uint64(x[:8]), binary.BigEndian.Uint64(x[8:])
+}
+
+func gfFromHalves(hi, lo uint64) GfElement {
+	var res GfElement
+	binary.BigEndian.PutUint64(res[:8], hi)
+	binary.BigEndian.PutUint64(res[8:], lo)
+	return res
+}
+
+func (x GfElement) Add(y GfElement) GfElement {
+	for i := range x {
+		x[i] ^= y[i]
+	}
+	return x
+}
+
+// Mul is algorithm 1 from SP 800-38D.
+func (x GfElement) Mul(y GfElement) GfElement {
+	xHi, xLo := x.halves()
+	vHi, vLo := y.halves()
+	var zHi, zLo uint64
+	for i := 0; i < 128; i++ {
+		var bit uint64
+		if i < 64 {
+			bit = (xHi >> (63 - i)) & 1
+		} else {
+			bit = (xLo >> (127 - i)) & 1
+		}
+		if bit == 1 {
+			zHi ^= vHi
+			zLo ^= vLo
+		}
+		carry := vLo & 1
+		vLo = vLo>>1 | vHi<<63
+		vHi >>= 1
+		if carry == 1 {
+			vHi ^= 0xe1 << 56
+		}
+	}
+	return gfFromHalves(zHi, zLo)
+}
+
+// Ghash is the GHASH polynomial accumulator keyed with H.
+type Ghash struct {
+	H   GfElement
+	Acc GfElement
+}
+
+// Update absorbs data, zero-padded to a multiple of 16 bytes.
+func (g *Ghash) Update(data []byte) {
+	for i := 0; i < len(data); i += 16 {
+		var x GfElement
+		copy(x[:], data[i:])
+		g.Acc = g.Acc.Add(x).Mul(g.H)
+	}
+}
+
+// UpdateLengths absorbs the final block holding the bit lengths of the AAD and the ciphertext.
+func (g *Ghash) UpdateLengths(aadLen int, ciphertextLen int) {
+	var x GfElement
+	binary.BigEndian.PutUint64(x[:8], uint64(aadLen)*8)
+	binary.BigEndian.PutUint64(x[8:], uint64(ciphertextLen)*8)
+	g.Update(x[:])
+}
+
+// Gcm is AES-GCM with all the internals available, so that nonce reuse and
+// truncated tag attacks can be mounted against it. It implements cipher.AEAD.
+type Gcm struct {
+	block   cipher.Block
+	H       GfElement
+	TagSize int
+}
+
+func NewGcm(block cipher.Block, tagSize int) (*Gcm, error) {
+	if block.BlockSize() != 16 {
+		return nil, fmt.Errorf("%w: GCM needs a 16-byte block cipher, got %d", ErrBadBlockSize, block.BlockSize())
+	}
+	if tagSize < 1 || tagSize > 16 {
+		return nil, fmt.Errorf("tag size must be between 1 and 16 bytes, got %d", tagSize)
+	}
+
+	res := &Gcm{block: block, TagSize: tagSize}
+	block.Encrypt(res.H[:], res.H[:])
+	return res, nil
+}
+
+func NewAesGcm(key []byte, tagSize int) (*Gcm, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return NewGcm(block, tagSize)
+}
+
+func (g *Gcm) NonceSize() int {
+	return 12
+}
+
+func (g *Gcm) Overhead() int {
+	return g.TagSize
+}
+
+// J0 is the pre-counter block: the tag mask is derived from it, and encryption starts right after it.
+func (g *Gcm) J0(nonce []byte) GfElement {
+	var res GfElement
+	if len(nonce) == 12 {
+		copy(res[:], nonce)
+		res[15] = 1
+		return res
+	}
+	ghash := g.NewGhash()
+	ghash.Update(nonce)
+	ghash.UpdateLengths(0, len(nonce))
+	return ghash.Acc
+}
+
+// TagMask is the block XORed into the GHASH output to produce the full-length tag.
+func (g *Gcm) TagMask(nonce []byte) GfElement {
+	j0 := g.J0(nonce)
+	var res GfElement
+	g.block.Encrypt(res[:], j0[:])
+	return res
+}
+
+func (g *Gcm) NewGhash() *Ghash {
+	return &Ghash{H: g.H}
+}
+
+// Ghash returns the accumulator after absorbing the AAD, the ciphertext and their lengths.
+func (g *Gcm) Ghash(aad []byte, ciphertext []byte) GfElement {
+	ghash := g.NewGhash()
+	ghash.Update(aad)
+	ghash.Update(ciphertext)
+	ghash.UpdateLengths(len(aad), len(ciphertext))
+	return ghash.Acc
+}
+
+func (g *Gcm) tag(nonce []byte, ciphertext []byte, aad []byte) []byte {
+	tag := g.Ghash(aad, ciphertext).Add(g.TagMask(nonce))
+	return tag[:g.TagSize]
+}
+
+func (g *Gcm) crypt(dst []byte, nonce []byte, src []byte) {
+	j0 := g.J0(nonce)
+	layout := CtrLayout{
+		CounterOffset: 12,
+		CounterSize:   4,
+		BigEndian:     true,
+		Initial:       uint64(binary.BigEndian.Uint32(j0[12:]) + 1),
+	}
+	stream, err := NewCtr(g.block, j0[:12], layout)
+	if err != nil {
+		panic(err)
+	}
+	stream.XORKeyStream(dst, src)
+}
+
+func (g *Gcm) checkNonce(nonce []byte) {
+	if len(nonce) == 0 {
+		panic("util: GCM nonce must not be empty")
+	}
+}
+
+// sliceForAppend grows in by n bytes without touching them, so that the input of an in-place
+// call (dst = input[:0]) survives until it is read. It returns the grown slice and its tail.
+func sliceForAppend(in []byte, n int) (head []byte, tail []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		head = in[:total]
+	} else {
+		head = make([]byte, total)
+		copy(head, in)
+	}
+	return head, head[len(in):]
+}
+
+func (g *Gcm) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	g.checkNonce(nonce)
+	res, ciphertext := sliceForAppend(dst, len(plaintext)+g.TagSize)
+	ciphertext = ciphertext[:len(plaintext)]
+	g.crypt(ciphertext, nonce, plaintext)
+	tag := g.tag(nonce, ciphertext, additionalData)
+	copy(res[len(res)-g.TagSize:], tag)
+	return res
+}
+
+func (g *Gcm) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	g.checkNonce(nonce)
+	if len(ciphertext) < g.TagSize {
+		return nil, fmt.Errorf("%w: ciphertext is shorter than the %d-byte tag", ErrBadLength, g.TagSize)
+	}
+	tag := ciphertext[len(ciphertext)-g.TagSize:]
+	ciphertext = ciphertext[:len(ciphertext)-g.TagSize]
+	if subtle.ConstantTimeCompare(tag, g.tag(nonce, ciphertext, additionalData)) != 1 {
+		return nil, ErrAuthFailed
+	}
+
+	res, plaintext := sliceForAppend(dst, len(ciphertext))
+	g.crypt(plaintext, nonce, ciphertext)
+	return res, nil
+}
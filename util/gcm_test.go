@@ -0,0 +1,106 @@
+package util
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestGcmVector(t *testing.T) {
+	// Test case 2 from the original GCM specification.
+	g, err := NewAesGcm(make([]byte, 16), 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedH, _ := hex.DecodeString("66e94bd4ef8a2c3b884cfa59ca342b2e")
+	if !bytes.Equal(g.H[:], expectedH) {
+		t.Fatalf("Expected H = %x, got %x", expectedH, g.H)
+	}
+	expected, _ := hex.DecodeString("0388dace60b6a392f328c2b971b2fe78ab6e47d42cec13bdf53a67b21257bddf")
+	actual := g.Seal(nil, make([]byte, 12), make([]byte, 16), nil)
+	if !bytes.Equal(actual, expected) {
+		t.Fatalf("Expected %x, got %x", expected, actual)
+	}
+
+	// The tag is the accumulator masked with E(K, J0).
+	ghash := g.Ghash(nil, actual[:16])
+	tag := ghash.Add(g.TagMask(make([]byte, 12)))
+	if !bytes.Equal(tag[:], expected[16:]) {
+		t.Fatalf("Expected tag %x, got %x", expected[16:], tag)
+	}
+}
+
+func TestGcmMatchesStdlib(t *testing.T) {
+	key := []byte("YELLOW SUBMARINE")
+	block, _ := aes.NewCipher(key)
+	for _, tt := range []struct {
+		nonceSize int
+		tagSize   int
+	}{{12, 16}, {12, 12}, {8, 16}, {16, 16}, {60, 16}} {
+		var std cipher.AEAD
+		var err error
+		if tt.nonceSize == 12 {
+			std, err = cipher.NewGCMWithTagSize(block, tt.tagSize)
+		} else {
+			std, err = cipher.NewGCMWithNonceSize(block, tt.nonceSize)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		g, err := NewGcm(block, tt.tagSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		nonce := bytes.Repeat([]byte{0xca}, tt.nonceSize)
+		for _, size := range []int{0, 1, 16, 17, 100} {
+			plaintext := bytes.Repeat([]byte("P"), size)
+			aad := bytes.Repeat([]byte("A"), size/3)
+			expected := std.Seal(nil, nonce, plaintext, aad)
+			actual := g.Seal(nil, nonce, plaintext, aad)
+			if !bytes.Equal(actual, expected) {
+				t.Fatalf("Expected %x for nonce %d, tag %d and %d bytes, got %x", expected, tt.nonceSize, tt.tagSize, size, actual)
+			}
+			opened, err := g.Open(nil, nonce, actual, aad)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(opened, plaintext) {
+				t.Fatalf("Expected %q, got %q", plaintext, opened)
+			}
+
+			// In place, like cipher.AEAD allows it.
+			buf := append(make([]byte, 0, size+tt.tagSize), plaintext...)
+			sealed := g.Seal(buf[:0], nonce, buf, aad)
+			if !bytes.Equal(sealed, expected) {
+				t.Fatalf("Expected %x in place, got %x", expected, sealed)
+			}
+			opened, err = g.Open(sealed[:0], nonce, sealed, aad)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(opened, plaintext) {
+				t.Fatalf("Expected %q in place, got %q", plaintext, opened)
+			}
+		}
+	}
+}
+
+func TestGcmTruncatedTag(t *testing.T) {
+	g, _ := NewAesGcm([]byte("YELLOW SUBMARINE"), 4)
+	full, _ := NewAesGcm([]byte("YELLOW SUBMARINE"), 16)
+	nonce := make([]byte, 12)
+	sealed := g.Seal(nil, nonce, []byte("ICE ICE BABY"), nil)
+	fullSealed := full.Seal(nil, nonce, []byte("ICE ICE BABY"), nil)
+	if !bytes.Equal(sealed, fullSealed[:len(sealed)]) {
+		t.Fatalf("Expected a prefix of %x, got %x", fullSealed, sealed)
+	}
+
+	sealed[0] ^= 1
+	if _, err := g.Open(nil, nonce, sealed, nil); !errors.Is(err, ErrAuthFailed) {
+		t.Fatalf("Expected ErrAuthFailed, got %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+)
+
+// DumpOptions control BlockDump and BlockDiff. Repeated blocks are marked with '*'
+// and differing ones with '!' in the margin, and additionally colored with Color.
+type DumpOptions struct {
+	BlockSize int
+	Color     bool
+}
+
+func (opts DumpOptions) blockSize() int {
+	if opts.BlockSize <= 0 {
+		return AesBlockSize
+	}
+	return opts.BlockSize
+}
+
+func (opts DumpOptions) paint(s string, color string) string {
+	if !opts.Color || color == "" {
+		return s
+	}
+	return color + s + ansiReset
+}
+
+func printable(b byte) byte {
+	if b < 0x20 || b > 0x7e {
+		return '.'
+	}
+	return b
+}
+
+// dumpBlock prints one line; byteColor (if not nil) gives the color of every byte.
+func (opts DumpOptions) dumpBlock(w io.Writer, offset int, block []byte, marker byte, lineColor string, byteColor func(int) string) error {
+	bs := opts.blockSize()
+	var hexPart, asciiPart strings.Builder
+	for i := 0; i < bs; i++ {
+		if i > 0 {
+			hexPart.WriteByte(' ')
+		}
+		if i >= len(block) {
+			hexPart.WriteString("  ")
+			continue
+		}
+		color := lineColor
+		if byteColor != nil {
+			color = byteColor(i)
+		}
+		hexPart.WriteString(opts.paint(fmt.Sprintf("%02x", block[i]), color))
+		asciiPart.WriteString(opts.paint(string(printable(block[i])), color))
+	}
+	_, err := fmt.Fprintf(w, "%08x %c %s |%s|\n", offset, marker, hexPart.String(), asciiPart.String())
+	return err
+}
+
+// BlockDump prints data split into blocks with offsets and an ASCII column.
+// Blocks occurring more than once (a telltale sign of ECB) are highlighted.
+func BlockDump(w io.Writer, data []byte, opts DumpOptions) error {
+	bs := opts.blockSize()
+	counts := make(map[string]int)
+	for i := 0; i+bs <= len(data); i += bs {
+		counts[string(data[i:i+bs])]++
+	}
+
+	for i := 0; i < len(data); i += bs {
+		end := i + bs
+		if end > len(data) {
+			end = len(data)
+		}
+		marker, color := byte(' '), ""
+		if counts[string(data[i:end])] > 1 {
+			marker, color = '*', ansiYellow
+		}
+		if err := opts.dumpBlock(w, i, data[i:end], marker, color, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BlockDiff prints a and b block by block, one under the other. The blocks that differ are
+// marked, and the differing bytes inside them are highlighted.
+func BlockDiff(w io.Writer, a []byte, b []byte, opts DumpOptions) error {
+	bs := opts.blockSize()
+	maxLen := len(a)
+	if len(b) > maxLen {
+		maxLen = len(b)
+	}
+	slice := func(data []byte, start int) []byte {
+		if start >= len(data) {
+			return nil
+		}
+		end := start + bs
+		if end > len(data) {
+			end = len(data)
+		}
+		return data[start:end]
+	}
+
+	for i := 0; i < maxLen; i += bs {
+		blockA, blockB := slice(a, i), slice(b, i)
+		marker := byte(' ')
+		if string(blockA) != string(blockB) {
+			marker = '!'
+		}
+		byteColor := func(j int) string {
+			if j >= len(blockA) && j >= len(blockB) {
+				return ""
+			}
+			if j >= len(blockA) || j >= len(blockB) || blockA[j] != blockB[j] {
+				return ansiRed
+			}
+			return ""
+		}
+		if err := opts.dumpBlock(w, i, blockA, marker, "", byteColor); err != nil {
+			return err
+		}
+		if err := opts.dumpBlock(w, i, blockB, marker, "", byteColor); err != nil {
+			return err
+		}
+		if marker != ' ' && !opts.Color {
+			if err := opts.dumpCarets(w, byteColor); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// dumpCarets points at the differing bytes when colors are not available.
+func (opts DumpOptions) dumpCarets(w io.Writer, byteColor func(int) string) error {
+	bs := opts.blockSize()
+	var hexPart, asciiPart strings.Builder
+	for i := 0; i < bs; i++ {
+		if i > 0 {
+			hexPart.WriteByte(' ')
+		}
+		if byteColor(i) != "" {
+			hexPart.WriteString("^^")
+			asciiPart.WriteByte('^')
+		} else {
+			hexPart.WriteString("  ")
+			asciiPart.WriteByte(' ')
+		}
+	}
+	_, err := fmt.Fprintf(w, "%8s   %s  %s\n", "", hexPart.String(), strings.TrimRight(asciiPart.String(), " "))
+	return err
+}
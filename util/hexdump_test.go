@@ -0,0 +1,50 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBlockDump(t *testing.T) {
+	var out strings.Builder
+	data := []byte("YELLOW SUBMARINEYELLOW SUBMARINE\x00\x01abc")
+	if err := BlockDump(&out, data, DumpOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	expected := "" +
+		"00000000 * 59 45 4c 4c 4f 57 20 53 55 42 4d 41 52 49 4e 45 |YELLOW SUBMARINE|\n" +
+		"00000010 * 59 45 4c 4c 4f 57 20 53 55 42 4d 41 52 49 4e 45 |YELLOW SUBMARINE|\n" +
+		"00000020   00 01 61 62 63                                  |..abc|\n"
+	if out.String() != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, out.String())
+	}
+
+	out.Reset()
+	BlockDump(&out, data[:16], DumpOptions{BlockSize: 8, Color: true})
+	if strings.Contains(out.String(), ansiYellow) || !strings.HasPrefix(out.String(), "00000000   59") {
+		t.Fatalf("Unexpected output %q", out.String())
+	}
+}
+
+func TestBlockDiff(t *testing.T) {
+	var out strings.Builder
+	err := BlockDiff(&out, []byte("ICE ICE BABY"), []byte("ICE ICE BAYB!"), DumpOptions{BlockSize: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "" +
+		"00000000   49 43 45 20 49 43 45 20 |ICE ICE |\n" +
+		"00000000   49 43 45 20 49 43 45 20 |ICE ICE |\n" +
+		"00000008 ! 42 41 42 59             |BABY|\n" +
+		"00000008 ! 42 41 59 42 21          |BAYB!|\n" +
+		"                 ^^ ^^ ^^             ^^^\n"
+	if out.String() != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, out.String())
+	}
+
+	out.Reset()
+	BlockDiff(&out, []byte("ICE ICE BABY"), []byte("ICE ICE BAYB!"), DumpOptions{BlockSize: 8, Color: true})
+	if !strings.Contains(out.String(), ansiRed+"59"+ansiReset) || strings.Contains(out.String(), "^") {
+		t.Fatalf("Unexpected output %q", out.String())
+	}
+}
@@ -0,0 +1,146 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type KV struct {
+	Key   string
+	Value string
+}
+
+type KVOptions struct {
+	// The separators default to '&' and '=' when left zero.
+	PairSeparator     byte
+	KeyValueSeparator byte
+	// With Escape, EncodeKV percent-encodes the metacharacters in keys and values, and the parser
+	// URL-decodes them back. Without it, EncodeKV rejects metacharacters and the parser keeps escapes as is.
+	Escape bool
+	// Strict makes the parser reject duplicate keys.
+	Strict bool
+}
+
+func (opts KVOptions) separators() (pairSep byte, kvSep byte) {
+	pairSep, kvSep = opts.PairSeparator, opts.KeyValueSeparator
+	if pairSep == 0 {
+		pairSep = '&'
+	}
+	if kvSep == 0 {
+		kvSep = '='
+	}
+	return
+}
+
+func (opts KVOptions) isMeta(b byte) bool {
+	pairSep, kvSep := opts.separators()
+	return b == pairSep || b == kvSep || (opts.Escape && b == '%') || b < 0x20 || b == 0x7f
+}
+
+func (opts KVOptions) escape(s string) (string, error) {
+	var res strings.Builder
+	for i := 0; i < len(s); i++ {
+		if !opts.isMeta(s[i]) {
+			res.WriteByte(s[i])
+		} else if opts.Escape {
+			fmt.Fprintf(&res, "%%%02X", s[i])
+		} else {
+			return "", fmt.Errorf("<%s> contains the forbidden character %q", s, s[i])
+		}
+	}
+	return res.String(), nil
+}
+
+func (opts KVOptions) unescape(s string) (string, error) {
+	if !opts.Escape || !strings.Contains(s, "%") {
+		return s, nil
+	}
+	var res strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			res.WriteByte(s[i])
+			continue
+		}
+		if i+2 >= len(s) {
+			return "", fmt.Errorf("<%s> has a truncated escape at position %d", s, i)
+		}
+		b, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+		if err != nil {
+			return "", fmt.Errorf("<%s> has an invalid escape at position %d", s, i)
+		}
+		res.WriteByte(byte(b))
+		i += 2
+	}
+	return res.String(), nil
+}
+
+func EncodeKV(pairs []KV, opts KVOptions) (string, error) {
+	pairSep, kvSep := opts.separators()
+	parts := make([]string, 0, len(pairs))
+	for _, kv := range pairs {
+		key, err := opts.escape(kv.Key)
+		if err != nil {
+			return "", err
+		}
+		value, err := opts.escape(kv.Value)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, key+string(kvSep)+value)
+	}
+	return strings.Join(parts, string(pairSep)), nil
+}
+
+// ParseKVPairs keeps the pairs in their original order.
+func ParseKVPairs(s string, opts KVOptions) ([]KV, error) {
+	pairSep, kvSep := opts.separators()
+	var res []KV
+	seen := make(map[string]bool)
+	hasMoreKV := true
+	for hasMoreKV {
+		kv := s
+		hasMoreKV = false
+		if sepPos := strings.IndexByte(s, pairSep); sepPos >= 0 {
+			kv = s[:sepPos]
+			s = s[sepPos+1:]
+			hasMoreKV = true
+		}
+
+		eqPos := strings.IndexByte(kv, kvSep)
+		if eqPos < 0 {
+			return nil, fmt.Errorf("<%s> is not a valid key/value pair", kv)
+		}
+		key, err := opts.unescape(kv[:eqPos])
+		if err != nil {
+			return nil, err
+		}
+		value, err := opts.unescape(kv[eqPos+1:])
+		if err != nil {
+			return nil, err
+		}
+		if opts.Strict && seen[key] {
+			return nil, fmt.Errorf("duplicate key <%s>", key)
+		}
+		seen[key] = true
+		res = append(res, KV{key, value})
+	}
+	return res, nil
+}
+
+// ParseKVWithOptions is like ParseKVPairs, but later values win for duplicate keys unless opts.Strict is set.
+func ParseKVWithOptions(s string, opts KVOptions) (map[string]string, error) {
+	pairs, err := ParseKVPairs(s, opts)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]string)
+	for _, kv := range pairs {
+		res[kv.Key] = kv.Value
+	}
+	return res, nil
+}
+
+func ParseKV(s string) (map[string]string, error) {
+	return ParseKVWithOptions(s, KVOptions{})
+}
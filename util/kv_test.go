@@ -0,0 +1,146 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseKV(t *testing.T) {
+	goodTests := []struct {
+		input    string
+		expected map[string]string
+	}{
+		{"lol=kek", map[string]string{
+			"lol": "kek",
+		}},
+		{"foo=bar&baz=qux&zap=zazzle", map[string]string{
+			"foo": "bar",
+			"baz": "qux",
+			"zap": "zazzle",
+		}},
+		{"=&role==admin&user=", map[string]string{
+			"":     "",
+			"role": "=admin",
+			"user": "",
+		}},
+	}
+	for _, tt := range goodTests {
+		actual, err := ParseKV(tt.input)
+		if err != nil {
+			t.Fatalf("Expected %v, got error %v", tt.expected, err)
+		}
+		if !reflect.DeepEqual(tt.expected, actual) {
+			t.Fatalf("Expected %v, got %v", tt.expected, actual)
+		}
+	}
+
+	badTests := []string{
+		"&",
+		"role=admin&&user=foo",
+		"blah=&",
+		"abc&def=ghi",
+	}
+	for _, tt := range badTests {
+		actual, err := ParseKV(tt)
+		if err == nil {
+			t.Fatalf("Expected an error, got %v", actual)
+		}
+	}
+}
+
+func TestEncodeKV(t *testing.T) {
+	pairs := []KV{
+		{"email", "foo@bar.com&role=admin"},
+		{"uid", "10"},
+		{"100%", "sure"},
+	}
+	if actual, err := EncodeKV(pairs, KVOptions{}); err == nil {
+		t.Fatalf("Expected an error, got %q", actual)
+	}
+
+	opts := KVOptions{Escape: true, Strict: true}
+	encoded, err := EncodeKV(pairs, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "email=foo@bar.com%26role%3Dadmin&uid=10&100%25=sure"
+	if encoded != expected {
+		t.Fatalf("Expected %q, got %q", expected, encoded)
+	}
+	decoded, err := ParseKVPairs(encoded, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, pairs) {
+		t.Fatalf("Expected %v, got %v", pairs, decoded)
+	}
+
+	// Without escaping, the escapes are kept verbatim.
+	raw, err := ParseKV(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw["email"] != "foo@bar.com%26role%3Dadmin" {
+		t.Fatalf("Unexpected email %q", raw["email"])
+	}
+}
+
+func TestParseKVStrict(t *testing.T) {
+	opts := KVOptions{Escape: true, Strict: true}
+	badTests := []string{
+		"role=user&role=admin",
+		"role=user&r%6Fle=admin",
+		"role=%",
+		"role=%4",
+		"role=%zz",
+	}
+	for _, tt := range badTests {
+		if actual, err := ParseKVWithOptions(tt, opts); err == nil {
+			t.Fatalf("%q: expected an error, got %v", tt, actual)
+		}
+	}
+
+	actual, err := ParseKVWithOptions("role=user&role=admin", KVOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual["role"] != "admin" {
+		t.Fatalf("Expected the last value to win, got %v", actual)
+	}
+}
+
+func TestKVSeparators(t *testing.T) {
+	opts := KVOptions{PairSeparator: ';', Escape: true}
+	actual, err := ParseKVWithOptions("comment1=cooking%20MCs;userdata=a&b;comment2=%20like%20a%20pound%20of%20bacon", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"comment1": "cooking MCs",
+		"userdata": "a&b",
+		"comment2": " like a pound of bacon",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected %v, got %v", expected, actual)
+	}
+
+	encoded, err := EncodeKV([]KV{{"userdata", ";admin=true"}}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if encoded != "userdata=%3Badmin%3Dtrue" {
+		t.Fatalf("Unexpected encoding %q", encoded)
+	}
+	if _, err := EncodeKV([]KV{{"userdata", "a;b"}}, KVOptions{PairSeparator: ';'}); err == nil {
+		t.Fatalf("Expected an error")
+	}
+
+	colon := KVOptions{PairSeparator: '\n', KeyValueSeparator: ':'}
+	actual, err = ParseKVWithOptions("Host:example.com\nCookie:a=b", colon)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual["Host"] != "example.com" || actual["Cookie"] != "a=b" {
+		t.Fatalf("Unexpected result %v", actual)
+	}
+}
@@ -0,0 +1,176 @@
+package util
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+	"strings"
+)
+
+// CbcMacWithIV is the last block of the PKCS#7-padded CBC encryption of message.
+func CbcMacWithIV(block cipher.Block, message []byte, iv []byte) ([]byte, error) {
+	encrypted, err := CbcEncrypt(block, message, iv)
+	if err != nil {
+		return nil, err
+	}
+	return encrypted[len(encrypted)-block.BlockSize():], nil
+}
+
+// CbcMac uses a fixed all-zero IV.
+func CbcMac(block cipher.Block, message []byte) ([]byte, error) {
+	return CbcMacWithIV(block, message, make([]byte, block.BlockSize()))
+}
+
+func AesCbcMac(message []byte, key []byte, iv []byte) ([]byte, error) {
+	encrypted, err := AesCbcEncrypt(message, key, iv)
+	if err != nil {
+		return nil, err
+	}
+	return encrypted[len(encrypted)-AesBlockSize:], nil
+}
+
+// cmacDouble multiplies by x in GF(2^n), as in the subkey generation of RFC 4493.
+func cmacDouble(input []byte) []byte {
+	res := make([]byte, len(input))
+	for i := range input {
+		res[i] = input[i] << 1
+		if i+1 < len(input) {
+			res[i] |= input[i+1] >> 7
+		}
+	}
+	if input[0]&0x80 != 0 {
+		if len(input) == 8 {
+			res[len(res)-1] ^= 0x1b
+		} else {
+			res[len(res)-1] ^= 0x87
+		}
+	}
+	return res
+}
+
+func Cmac(block cipher.Block, message []byte) ([]byte, error) {
+	bs := block.BlockSize()
+	if bs != 8 && bs != 16 {
+		return nil, fmt.Errorf("%w: CMAC is only defined for 8- and 16-byte blocks, got %d", ErrBadBlockSize, bs)
+	}
+
+	l := make([]byte, bs)
+	block.Encrypt(l, l)
+	k1 := cmacDouble(l)
+	k2 := cmacDouble(k1)
+
+	// A complete last block is masked with K1, a partial one is padded with 10...0 and masked with K2.
+	var padded []byte
+	subkey := k1
+	if len(message) > 0 && len(message)%bs == 0 {
+		padded = append([]byte(nil), message...)
+	} else {
+		padded, _ = Iso7816.Pad(message, bs)
+		subkey = k2
+	}
+	last := padded[len(padded)-bs:]
+	for i := range last {
+		last[i] ^= subkey[i]
+	}
+
+	newCbcEncrypter(block, make([]byte, bs)).CryptBlocks(padded, padded)
+	return padded[len(padded)-bs:], nil
+}
+
+func AesCmac(message []byte, key []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return Cmac(block, message)
+}
+
+func xorBlocks(a []byte, b []byte) []byte {
+	res := make([]byte, len(a))
+	for i := range res {
+		res[i] = a[i] ^ b[i]
+	}
+	return res
+}
+
+// ForgeCbcMacIV returns the IV under which message, with its first block replaced by
+// newFirstBlock, has the same CBC-MAC that message has under iv.
+func ForgeCbcMacIV(message []byte, iv []byte, newFirstBlock []byte) ([]byte, error) {
+	bs := len(iv)
+	if len(newFirstBlock) != bs || len(message) < bs {
+		return nil, fmt.Errorf("%w: need a full %d-byte first block, got %d and %d bytes", ErrBadLength, bs, len(message), len(newFirstBlock))
+	}
+	return xorBlocks(xorBlocks(iv, message[:bs]), newFirstBlock), nil
+}
+
+// ForgeCbcMacExtension glues two messages authenticated with the same key and an all-zero IV.
+// The result has the MAC of message2, given that mac1 is the MAC of message1.
+func ForgeCbcMacExtension(message1 []byte, mac1 []byte, message2 []byte) ([]byte, error) {
+	bs := len(mac1)
+	if len(message2) < bs {
+		return nil, fmt.Errorf("%w: the second message needs at least %d bytes, got %d", ErrBadLength, bs, len(message2))
+	}
+	res, err := PKCS7Pad(message1, bs)
+	if err != nil {
+		return nil, err
+	}
+	res = append(res, xorBlocks(message2[:bs], mac1)...)
+	return append(res, message2[bs:]...), nil
+}
+
+// ForgeCbcMacCollision returns prefix followed by a glue block and all but the first block of
+// original, so that the result has the same CBC-MAC as original. The prefix must be block-aligned.
+func ForgeCbcMacCollision(block cipher.Block, iv []byte, prefix []byte, original []byte) ([]byte, error) {
+	bs := block.BlockSize()
+	if err := checkIV(iv, bs); err != nil {
+		return nil, err
+	}
+	if err := checkLength(prefix, bs); err != nil {
+		return nil, err
+	}
+	if len(original) < bs {
+		return nil, fmt.Errorf("%w: the original message needs at least %d bytes, got %d", ErrBadLength, bs, len(original))
+	}
+
+	// After the glue block the chaining value has to be E(iv ^ original[0]), which is
+	// what it would be after the first block of the original message.
+	state := iv
+	if len(prefix) > 0 {
+		encrypted := make([]byte, len(prefix))
+		newCbcEncrypter(block, iv).CryptBlocks(encrypted, prefix)
+		state = encrypted[len(encrypted)-bs:]
+	}
+	glue := xorBlocks(xorBlocks(state, iv), original[:bs])
+
+	res := append(append([]byte(nil), prefix...), glue...)
+	return append(res, original[bs:]...), nil
+}
+
+// ForgeJsCbcMacHash makes a snippet that starts with the js code and has the same CBC-MAC
+// (with an all-zero IV) as original. Everything after the js code is hidden in a line comment,
+// so the rest of original must be a single line, and the glue block is chosen to contain no line breaks.
+func ForgeJsCbcMacHash(block cipher.Block, original string, js string) (string, error) {
+	bs := block.BlockSize()
+	if len(original) <= bs || !strings.HasSuffix(original, "\n") || strings.ContainsAny(original[bs:len(original)-1], "\r\n") {
+		return "", fmt.Errorf("everything after the first block of %q must be a single line", original)
+	}
+
+	prefix := []byte(js + "//")
+	for attempt := 0; attempt < 1000; attempt++ {
+		aligned := append([]byte(nil), prefix...)
+		for len(aligned)%bs != 0 {
+			aligned = append(aligned, ' ')
+		}
+		forged, err := ForgeCbcMacCollision(block, make([]byte, bs), aligned, []byte(original))
+		if err != nil {
+			return "", err
+		}
+		if !bytes.ContainsAny(forged[len(aligned):len(aligned)+bs], "\r\n") {
+			return string(forged), nil
+		}
+		// Any change to the comment gives a different glue block.
+		prefix = append(prefix, '*')
+	}
+	return "", fmt.Errorf("failed to find a glue block without line breaks")
+}
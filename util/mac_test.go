@@ -0,0 +1,88 @@
+package util
+
+import (
+	"bytes"
+	"crypto/aes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestCmac(t *testing.T) {
+	// RFC 4493, section 4.
+	key, _ := hex.DecodeString("2b7e151628aed2a6abf7158809cf4f3c")
+	message, _ := hex.DecodeString("6bc1bee22e409f96e93d7e117393172aae2d8a571e03ac9c9eb76fac45af8e5130c81c46a35ce411e5fbc1191a0a52eff69f2445df4f9b17ad2b417be66c3710")
+	tests := []struct {
+		length   int
+		expected string
+	}{
+		{0, "bb1d6929e95937287fa37d129b756746"},
+		{16, "070a16b46b4d4144f79bdd9dd04a287c"},
+		{40, "dfa66747de9ae63030ca32611497c827"},
+		{64, "51f0bebf7e3b9d92fc49741779363cfe"},
+	}
+	for _, tt := range tests {
+		expected, _ := hex.DecodeString(tt.expected)
+		actual, err := AesCmac(message[:tt.length], key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(actual, expected) {
+			t.Fatalf("%d bytes: expected %x, got %x", tt.length, expected, actual)
+		}
+	}
+}
+
+func TestCbcMacForgeries(t *testing.T) {
+	key := []byte("YELLOW SUBMARINE")
+	block, _ := aes.NewCipher(key)
+
+	// Challenge 50.
+	original := "alert('MZA who was that?');\n"
+	expectedHash, _ := hex.DecodeString("296b8d7cb78a243dda4d0a61d33bbdd1")
+	hash, err := CbcMac(block, []byte(original))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(hash, expectedHash) {
+		t.Fatalf("Expected %x, got %x", expectedHash, hash)
+	}
+	js := "alert('Ayo, the Wu is back!');"
+	forgedJs, err := ForgeJsCbcMacHash(block, original, js)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forgedHash, _ := CbcMac(block, []byte(forgedJs))
+	if !strings.HasPrefix(forgedJs, js+"//") || strings.Count(forgedJs, "\n") != 1 || !bytes.Equal(forgedHash, hash) {
+		t.Fatalf("Bad forgery %q with hash %x", forgedJs, forgedHash)
+	}
+
+	// Challenge 49, attacker-controlled IV.
+	iv := []byte("0123456789abcdef")
+	message := []byte("from=1&to=2&amount=1000000")
+	mac, _ := AesCbcMac(message, key, iv)
+	forged := append([]byte("from=3&to=2&amou"), message[AesBlockSize:]...)
+	forgedIv, err := ForgeCbcMacIV(message, iv, forged[:AesBlockSize])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if forgedMac, _ := AesCbcMac(forged, key, forgedIv); !bytes.Equal(forgedMac, mac) {
+		t.Fatalf("Expected %x, got %x", mac, forgedMac)
+	}
+
+	// Challenge 49, length extension with a fixed IV.
+	message1 := []byte("from=1&tx_list=2:5000")
+	message2 := []byte("from=3&tx_list=;3:1000000")
+	mac1, _ := CbcMac(block, message1)
+	mac2, _ := CbcMac(block, message2)
+	extended, err := ForgeCbcMacExtension(message1, mac1, message2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(extended, message1) || !bytes.HasSuffix(extended, message2[AesBlockSize:]) {
+		t.Fatalf("Unexpected extended message %q", extended)
+	}
+	if extendedMac, _ := CbcMac(block, extended); !bytes.Equal(extendedMac, mac2) {
+		t.Fatalf("Expected %x, got %x", mac2, extendedMac)
+	}
+}
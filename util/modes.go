@@ -0,0 +1,90 @@
+package util
+
+import (
+	"crypto/cipher"
+)
+
+type ecbEncrypter struct {
+	block cipher.Block
+}
+
+func (m ecbEncrypter) BlockSize() int {
+	return m.block.BlockSize()
+}
+
+func (m ecbEncrypter) CryptBlocks(dst, src []byte) {
+	bs := m.block.BlockSize()
+	for i := 0; i < len(src); i += bs {
+		m.block.Encrypt(dst[i:i+bs], src[i:i+bs])
+	}
+}
+
+type ecbDecrypter struct {
+	block cipher.Block
+}
+
+func (m ecbDecrypter) BlockSize() int {
+	return m.block.BlockSize()
+}
+
+func (m ecbDecrypter) CryptBlocks(dst, src []byte) {
+	bs := m.block.BlockSize()
+	for i := 0; i < len(src); i += bs {
+		m.block.Decrypt(dst[i:i+bs], src[i:i+bs])
+	}
+}
+
+type cbcEncrypter struct {
+	block cipher.Block
+	prev  []byte
+}
+
+func newCbcEncrypter(block cipher.Block, iv []byte) *cbcEncrypter {
+	return &cbcEncrypter{block: block, prev: append([]byte(nil), iv...)}
+}
+
+func (m *cbcEncrypter) BlockSize() int {
+	return m.block.BlockSize()
+}
+
+func (m *cbcEncrypter) CryptBlocks(dst, src []byte) {
+	bs := m.block.BlockSize()
+	for i := 0; i < len(src); i += bs {
+		for j := 0; j < bs; j++ {
+			m.prev[j] ^= src[i+j]
+		}
+		m.block.Encrypt(dst[i:i+bs], m.prev)
+		copy(m.prev, dst[i:i+bs])
+	}
+}
+
+type cbcDecrypter struct {
+	block cipher.Block
+	prev  []byte
+	tmp   []byte
+}
+
+func newCbcDecrypter(block cipher.Block, iv []byte) *cbcDecrypter {
+	return &cbcDecrypter{
+		block: block,
+		prev:  append([]byte(nil), iv...),
+		tmp:   make([]byte, block.BlockSize()),
+	}
+}
+
+func (m *cbcDecrypter) BlockSize() int {
+	return m.block.BlockSize()
+}
+
+func (m *cbcDecrypter) CryptBlocks(dst, src []byte) {
+	bs := m.block.BlockSize()
+	for i := 0; i < len(src); i += bs {
+		// Remember the ciphertext block first, since dst and src may be the same buffer.
+		copy(m.tmp, src[i:i+bs])
+		m.block.Decrypt(dst[i:i+bs], m.tmp)
+		for j := 0; j < bs; j++ {
+			dst[i+j] ^= m.prev[j]
+		}
+		m.prev, m.tmp = m.tmp, m.prev
+	}
+}
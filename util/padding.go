@@ -0,0 +1,200 @@
+package util
+
+import (
+	"fmt"
+)
+
+type Padding interface {
+	Pad(input []byte, blockSize int) ([]byte, error)
+	Unpad(input []byte, blockSize int) ([]byte, error)
+}
+
+var (
+	PKCS7    Padding = pkcs7Padding{}
+	AnsiX923 Padding = ansiX923Padding{}
+	Iso10126 Padding = NewIso10126(CryptoRand)
+	Iso7816  Padding = iso7816Padding{}
+	// Zero padding is ambiguous for plaintexts ending with zero bytes, since Unpad strips them.
+	ZeroPadding Padding = zeroPadding{}
+	NoPadding   Padding = noPadding{}
+)
+
+type pkcs7Padding struct{}
+
+func (pkcs7Padding) Pad(input []byte, blockSize int) ([]byte, error) {
+	return PKCS7Pad(input, blockSize)
+}
+
+func (pkcs7Padding) Unpad(input []byte, blockSize int) ([]byte, error) {
+	return PKCS7Unpad(input, blockSize)
+}
+
+// padWithLength appends the filler bytes followed by a single byte holding the padding length.
+func padWithLength(input []byte, blockSize int, filler func([]byte)) ([]byte, error) {
+	if err := checkBlockSize(blockSize); err != nil {
+		return nil, err
+	}
+
+	rem := blockSize - len(input)%blockSize
+	res := make([]byte, len(input)+rem)
+	copy(res, input)
+	filler(res[len(input) : len(res)-1])
+	res[len(res)-1] = byte(rem)
+	return res, nil
+}
+
+// checkPadded validates a padded input and returns its last byte.
+func checkPadded(input []byte, blockSize int) (byte, error) {
+	if err := checkBlockSize(blockSize); err != nil {
+		return 0, err
+	}
+	if len(input) == 0 {
+		return 0, fmt.Errorf("%w for block size %d: %d", ErrBadLength, blockSize, len(input))
+	}
+	if err := checkLength(input, blockSize); err != nil {
+		return 0, err
+	}
+	return input[len(input)-1], nil
+}
+
+func unpadWithLength(input []byte, blockSize int) (int, error) {
+	last, err := checkPadded(input, blockSize)
+	if err != nil {
+		return 0, err
+	}
+	if last == 0 || int(last) > blockSize {
+		return 0, &PaddingError{Err: ErrBadPaddingValue, Position: 1, Got: last, Expected: byte(blockSize)}
+	}
+	return int(last), nil
+}
+
+type ansiX923Padding struct{}
+
+func (ansiX923Padding) Pad(input []byte, blockSize int) ([]byte, error) {
+	return padWithLength(input, blockSize, func([]byte) {})
+}
+
+func (ansiX923Padding) Unpad(input []byte, blockSize int) ([]byte, error) {
+	nPad, err := unpadWithLength(input, blockSize)
+	if err != nil {
+		return nil, err
+	}
+	for i := 2; i <= nPad; i++ {
+		if cur := input[len(input)-i]; cur != 0 {
+			return nil, &PaddingError{Err: ErrBadPaddingBytes, Position: i, Got: cur, Expected: 0}
+		}
+	}
+	return input[:len(input)-nPad], nil
+}
+
+type iso10126Padding struct {
+	rng Rand
+}
+
+// NewIso10126 fills the padding with bytes from rng.
+func NewIso10126(rng Rand) Padding {
+	return iso10126Padding{rng}
+}
+
+func (p iso10126Padding) Pad(input []byte, blockSize int) ([]byte, error) {
+	var randErr error
+	res, err := padWithLength(input, blockSize, func(filler []byte) {
+		var random []byte
+		random, randErr = ReadRandBytes(p.rng, len(filler))
+		copy(filler, random)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if randErr != nil {
+		return nil, randErr
+	}
+	return res, nil
+}
+
+func (iso10126Padding) Unpad(input []byte, blockSize int) ([]byte, error) {
+	nPad, err := unpadWithLength(input, blockSize)
+	if err != nil {
+		return nil, err
+	}
+	return input[:len(input)-nPad], nil
+}
+
+type iso7816Padding struct{}
+
+func (iso7816Padding) Pad(input []byte, blockSize int) ([]byte, error) {
+	if err := checkBlockSize(blockSize); err != nil {
+		return nil, err
+	}
+
+	rem := blockSize - len(input)%blockSize
+	res := make([]byte, len(input)+rem)
+	copy(res, input)
+	res[len(input)] = 0x80
+	return res, nil
+}
+
+func (iso7816Padding) Unpad(input []byte, blockSize int) ([]byte, error) {
+	if _, err := checkPadded(input, blockSize); err != nil {
+		return nil, err
+	}
+	for i := 1; i <= blockSize; i++ {
+		switch cur := input[len(input)-i]; cur {
+		case 0x80:
+			return input[:len(input)-i], nil
+		case 0:
+		default:
+			return nil, &PaddingError{Err: ErrBadPaddingBytes, Position: i, Got: cur, Expected: 0x80}
+		}
+	}
+	return nil, &PaddingError{Err: ErrBadPaddingBytes, Position: blockSize, Got: 0, Expected: 0x80}
+}
+
+type zeroPadding struct{}
+
+func (zeroPadding) Pad(input []byte, blockSize int) ([]byte, error) {
+	if err := checkBlockSize(blockSize); err != nil {
+		return nil, err
+	}
+
+	res := make([]byte, (len(input)+blockSize-1)/blockSize*blockSize)
+	copy(res, input)
+	return res, nil
+}
+
+func (zeroPadding) Unpad(input []byte, blockSize int) ([]byte, error) {
+	if err := checkBlockSize(blockSize); err != nil {
+		return nil, err
+	}
+	if err := checkLength(input, blockSize); err != nil {
+		return nil, err
+	}
+
+	end := len(input)
+	for end > 0 && end > len(input)-blockSize && input[end-1] == 0 {
+		end--
+	}
+	return input[:end], nil
+}
+
+type noPadding struct{}
+
+func (noPadding) Pad(input []byte, blockSize int) ([]byte, error) {
+	if err := checkBlockSize(blockSize); err != nil {
+		return nil, err
+	}
+	if err := checkLength(input, blockSize); err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), input...), nil
+}
+
+func (noPadding) Unpad(input []byte, blockSize int) ([]byte, error) {
+	if err := checkBlockSize(blockSize); err != nil {
+		return nil, err
+	}
+	if err := checkLength(input, blockSize); err != nil {
+		return nil, err
+	}
+	return input, nil
+}
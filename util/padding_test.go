@@ -0,0 +1,109 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestPaddingSchemes(t *testing.T) {
+	input := []byte("ICE ICE BABY")
+	tests := []struct {
+		padding  Padding
+		expected []byte
+	}{
+		{PKCS7, []byte("ICE ICE BABY\x04\x04\x04\x04")},
+		{AnsiX923, []byte("ICE ICE BABY\x00\x00\x00\x04")},
+		{Iso7816, []byte("ICE ICE BABY\x80\x00\x00\x00")},
+		{ZeroPadding, []byte("ICE ICE BABY\x00\x00\x00\x00")},
+		{Iso10126, nil},
+	}
+	for _, tt := range tests {
+		padded, err := tt.padding.Pad(input, AesBlockSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.expected != nil && !bytes.Equal(padded, tt.expected) {
+			t.Fatalf("%T: expected %q, got %q", tt.padding, tt.expected, padded)
+		}
+		if len(padded) != AesBlockSize {
+			t.Fatalf("%T: unexpected padding %q", tt.padding, padded)
+		}
+		unpadded, err := tt.padding.Unpad(padded, AesBlockSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(unpadded, input) {
+			t.Fatalf("%T: expected %q, got %q", tt.padding, input, unpadded)
+		}
+	}
+
+	aligned := []byte("YELLOW SUBMARINE")
+	for _, p := range []Padding{PKCS7, AnsiX923, Iso10126, Iso7816} {
+		padded, _ := p.Pad(aligned, AesBlockSize)
+		if len(padded) != 2*AesBlockSize {
+			t.Fatalf("%T: expected a full block of padding, got %q", p, padded)
+		}
+	}
+	for _, p := range []Padding{ZeroPadding, NoPadding} {
+		padded, _ := p.Pad(aligned, AesBlockSize)
+		if !bytes.Equal(padded, aligned) {
+			t.Fatalf("%T: expected no padding, got %q", p, padded)
+		}
+	}
+	if _, err := NoPadding.Pad(input, AesBlockSize); !errors.Is(err, ErrBadLength) {
+		t.Fatalf("Expected ErrBadLength, got %v", err)
+	}
+}
+
+func TestBadPadding(t *testing.T) {
+	tests := []struct {
+		padding  Padding
+		input    string
+		expected error
+	}{
+		{AnsiX923, "ICE ICE BABY\x00\x01\x00\x04", ErrBadPaddingBytes},
+		{AnsiX923, "ICE ICE BABY\x00\x00\x00\x00", ErrBadPaddingValue},
+		{Iso10126, "ICE ICE BABY\x00\x00\x00\x20", ErrBadPaddingValue},
+		{Iso7816, "ICE ICE BABY\x00\x00\x00\x01", ErrBadPaddingBytes},
+		{Iso7816, "\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00", ErrBadPaddingBytes},
+		{Iso7816, "ICE ICE BABY\x80", ErrBadLength},
+	}
+	for _, tt := range tests {
+		_, err := tt.padding.Unpad([]byte(tt.input), AesBlockSize)
+		if !errors.Is(err, tt.expected) {
+			t.Fatalf("%T.Unpad(%q): expected %v, got %v", tt.padding, tt.input, tt.expected, err)
+		}
+	}
+}
+
+func TestCbcWithPadding(t *testing.T) {
+	key := []byte("YELLOW SUBMARINE")
+	iv := make([]byte, AesBlockSize)
+	plaintext := []byte("Burning 'em, if you ain't quick and nimble")
+	for _, p := range []Padding{PKCS7, AnsiX923, Iso10126, Iso7816} {
+		encrypted, err := AesCbcEncryptWithPadding(plaintext, key, iv, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decrypted, err := AesCbcDecryptWithPadding(encrypted, key, iv, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decrypted, plaintext) {
+			t.Fatalf("%T: expected %q, got %q", p, plaintext, decrypted)
+		}
+
+		encrypted, err = AesEcbEncryptWithPadding(plaintext, key, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decrypted, err = AesEcbDecryptWithPadding(encrypted, key, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decrypted, plaintext) {
+			t.Fatalf("%T: expected %q, got %q", p, plaintext, decrypted)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package util
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+)
+
+// PaddingOracle tells whether iv and ciphertext decrypt to a message with valid PKCS#7 padding.
+type PaddingOracle func(iv []byte, ciphertext []byte) bool
+
+// NewCbcPaddingOracle is the vulnerable server: it decrypts with block and leaks the padding validity.
+func NewCbcPaddingOracle(block cipher.Block) PaddingOracle {
+	return func(iv []byte, ciphertext []byte) bool {
+		decrypted, err := CbcDecrypt(block, ciphertext, iv)
+		if err != nil {
+			return false
+		}
+		_, err = PKCS7.Unpad(decrypted, block.BlockSize())
+		return err == nil
+	}
+}
+
+// NewAesCbcPaddingOracle is the same server for AES, built on AesCbcDecrypt like the challenge describes it.
+func NewAesCbcPaddingOracle(key []byte) (PaddingOracle, error) {
+	if _, err := aes.NewCipher(key); err != nil {
+		return nil, err
+	}
+	key = append([]byte(nil), key...)
+	return func(iv []byte, ciphertext []byte) bool {
+		decrypted, err := AesCbcDecrypt(ciphertext, key, iv)
+		if err != nil {
+			return false
+		}
+		_, err = PKCS7Unpad(decrypted, AesBlockSize)
+		return err == nil
+	}, nil
+}
+
+// countingOracle counts the queries made to the wrapped oracle.
+type countingOracle struct {
+	oracle  PaddingOracle
+	queries int
+}
+
+func (o *countingOracle) query(iv []byte, ciphertext []byte) bool {
+	o.queries++
+	return o.oracle(iv, ciphertext)
+}
+
+// intermediate finds D(target), the block cipher decryption of a single ciphertext block,
+// by forging the block that precedes it byte by byte from the end.
+func (o *countingOracle) intermediate(target []byte) ([]byte, error) {
+	bs := len(target)
+	res := make([]byte, bs)
+	forged := make([]byte, bs)
+	for pos := bs - 1; pos >= 0; pos-- {
+		pad := byte(bs - pos)
+		for i := pos + 1; i < bs; i++ {
+			forged[i] = res[i] ^ pad
+		}
+
+		found := false
+		for guess := 0; guess <= 0xff && !found; guess++ {
+			forged[pos] = byte(guess)
+			if !o.query(forged, target) {
+				continue
+			}
+			// For the last byte, a valid padding can also be 0x02 0x02, 0x03 0x03 0x03 and so on,
+			// if the preceding bytes happen to decrypt to that. Changing the byte before
+			// the last one only keeps the padding valid if it is really 0x01.
+			if pos == bs-1 && bs > 1 {
+				forged[pos-1] ^= 0xff
+				valid := o.query(forged, target)
+				forged[pos-1] ^= 0xff
+				if !valid {
+					continue
+				}
+			}
+			res[pos] = byte(guess) ^ pad
+			found = true
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: no byte gives valid padding at position %d", ErrBadOracle, pos)
+		}
+	}
+	return res, nil
+}
+
+// PaddingOracleDecrypt recovers the plaintext of a CBC ciphertext, without the padding,
+// using only the oracle. It also returns the number of oracle queries it took.
+func PaddingOracleDecrypt(oracle PaddingOracle, iv []byte, ciphertext []byte) ([]byte, int, error) {
+	bs := len(iv)
+	if err := checkBlockSize(bs); err != nil {
+		return nil, 0, err
+	}
+	if err := checkLength(ciphertext, bs); err != nil {
+		return nil, 0, err
+	}
+	if len(ciphertext) == 0 {
+		return nil, 0, fmt.Errorf("%w: empty ciphertext", ErrBadLength)
+	}
+
+	o := &countingOracle{oracle: oracle}
+	plaintext := make([]byte, 0, len(ciphertext))
+	prev := iv
+	for i := 0; i < len(ciphertext); i += bs {
+		target := ciphertext[i : i+bs]
+		intermediate, err := o.intermediate(target)
+		if err != nil {
+			return nil, o.queries, fmt.Errorf("block %d: %w", i/bs, err)
+		}
+		plaintext = append(plaintext, xorBlocks(intermediate, prev)...)
+		prev = target
+	}
+
+	res, err := PKCS7.Unpad(plaintext, bs)
+	if err != nil {
+		return nil, o.queries, fmt.Errorf("%w: the recovered plaintext is not padded: %v", ErrBadOracle, err)
+	}
+	return res, o.queries, nil
+}
+
+// PaddingOracleEncrypt forges an IV and a ciphertext that decrypt to plaintext under the oracle's key
+// ("CBC-R"). Working backwards from a random last block, every preceding block is chosen so that
+// the next one decrypts to the right plaintext block. It also returns the number of oracle queries it took.
+// The oracle can't tell its block size, so blockSize must be the one of the oracle's cipher.
+func PaddingOracleEncrypt(oracle PaddingOracle, plaintext []byte, blockSize int, rng Rand) (iv []byte, ciphertext []byte, queries int, err error) {
+	if err := checkBlockSize(blockSize); err != nil {
+		return nil, nil, 0, err
+	}
+	padded, err := PKCS7.Pad(plaintext, blockSize)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	last, err := ReadRandBytes(rng, blockSize)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	o := &countingOracle{oracle: oracle}
+	n := len(padded) / blockSize
+	blocks := make([]byte, len(padded)+blockSize)
+	copy(blocks[n*blockSize:], last)
+	for i := n; i > 0; i-- {
+		intermediate, err := o.intermediate(blocks[i*blockSize : (i+1)*blockSize])
+		if err != nil {
+			// A wrong block size makes the oracle reject everything right away.
+			return nil, nil, o.queries, fmt.Errorf("block %d (is %d the block size of the oracle?): %w", i-1, blockSize, err)
+		}
+		copy(blocks[(i-1)*blockSize:], xorBlocks(intermediate, padded[(i-1)*blockSize:i*blockSize]))
+	}
+	return blocks[:blockSize], blocks[blockSize:], o.queries, nil
+}
@@ -0,0 +1,218 @@
+package util
+
+import (
+	"bytes"
+	"crypto/des"
+	"errors"
+	"testing"
+)
+
+// xorCipher "encrypts" by XORing with the key, so tests can pick the decrypted blocks.
+type xorCipher []byte
+
+func (c xorCipher) BlockSize() int {
+	return len(c)
+}
+
+func (c xorCipher) Encrypt(dst, src []byte) {
+	copy(dst, xorBlocks(src, c))
+}
+
+func (c xorCipher) Decrypt(dst, src []byte) {
+	copy(dst, xorBlocks(src, c))
+}
+
+func TestPaddingOracleDecrypt(t *testing.T) {
+	rng := NewSeededRand(17)
+	key := RandBytesFrom(rng, AesBlockSize)
+	oracle, err := NewAesCbcPaddingOracle(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, size := range []int{0, 1, 15, 16, 17, 50} {
+		plaintext := RandBytesFrom(rng, size)
+		iv := RandBytesFrom(rng, AesBlockSize)
+		ciphertext, err := AesCbcEncrypt(plaintext, key, iv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decrypted, queries, err := PaddingOracleDecrypt(oracle, iv, ciphertext)
+		if err != nil {
+			t.Fatalf("Expected no error for %d bytes, got %v", size, err)
+		}
+		if !bytes.Equal(decrypted, plaintext) {
+			t.Fatalf("Expected %x, got %x", plaintext, decrypted)
+		}
+		if queries < len(ciphertext) || queries > 257*len(ciphertext) {
+			t.Fatalf("Expected %v to %v queries, got %v", len(ciphertext), 257*len(ciphertext), queries)
+		}
+	}
+}
+
+func TestPaddingOracleDecryptDes(t *testing.T) {
+	block, err := des.NewCipher([]byte("8 bytes!"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext := []byte("DES has 8-byte blocks")
+	iv := []byte("init vec")
+	ciphertext, err := CbcEncrypt(block, plaintext, iv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decrypted, _, err := PaddingOracleDecrypt(NewCbcPaddingOracle(block), iv, ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Fatalf("Expected %q, got %q", plaintext, decrypted)
+	}
+}
+
+func TestPaddingOracleLastByteAmbiguity(t *testing.T) {
+	// With the forged block starting with zeros, the first ciphertext block decrypts to
+	// ... 0x02 0x02, so the first guess for the last byte that gives valid padding is 0x00,
+	// and it only does so because of 0x02 0x02.
+	block := xorCipher(make([]byte, 8))
+	plaintext := []byte("Ambiguous padding")
+	iv := make([]byte, 8)
+	iv[6] = 0x02 ^ plaintext[6]
+	iv[7] = 0x02 ^ plaintext[7]
+	ciphertext, err := CbcEncrypt(block, plaintext, iv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ciphertext[6] != 0x02 || ciphertext[7] != 0x02 {
+		t.Fatalf("Expected a first block ending with 0202, got %x", ciphertext[:8])
+	}
+	decrypted, _, err := PaddingOracleDecrypt(NewCbcPaddingOracle(block), iv, ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Fatalf("Expected %q, got %q", plaintext, decrypted)
+	}
+}
+
+func TestPaddingOracleDecryptErrors(t *testing.T) {
+	iv := make([]byte, AesBlockSize)
+	if _, _, err := PaddingOracleDecrypt(func([]byte, []byte) bool { return false }, iv, make([]byte, 16)); !errors.Is(err, ErrBadOracle) {
+		t.Fatalf("Expected %v, got %v", ErrBadOracle, err)
+	}
+	if _, _, err := PaddingOracleDecrypt(func([]byte, []byte) bool { return true }, iv, make([]byte, 15)); !errors.Is(err, ErrBadLength) {
+		t.Fatalf("Expected %v, got %v", ErrBadLength, err)
+	}
+}
+
+func TestPaddingOracleEncrypt(t *testing.T) {
+	rng := NewSeededRand(25)
+	key := RandBytesFrom(rng, AesBlockSize)
+	oracle, err := NewAesCbcPaddingOracle(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, plaintext := range []string{"", "admin=true", "comment1=cooking%20MCs;userdata=x;admin=true;comment2=%20like"} {
+		iv, ciphertext, queries, err := PaddingOracleEncrypt(oracle, []byte(plaintext), AesBlockSize, rng)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if queries == 0 {
+			t.Fatalf("Expected some queries, got %v", queries)
+		}
+		decrypted, err := AesCbcDecryptWithPadding(ciphertext, key, iv, PKCS7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(decrypted) != plaintext {
+			t.Fatalf("Expected %q, got %q", plaintext, decrypted)
+		}
+	}
+}
+
+func TestPaddingOracleEncryptDes(t *testing.T) {
+	block, err := des.NewCipher([]byte("8 bytes!"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext := []byte("forged with 8-byte blocks")
+	iv, ciphertext, _, err := PaddingOracleEncrypt(NewCbcPaddingOracle(block), plaintext, block.BlockSize(), NewSeededRand(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decrypted, err := CbcDecryptWithPadding(block, ciphertext, iv, PKCS7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Fatalf("Expected %q, got %q", plaintext, decrypted)
+	}
+}
+
+func TestPaddingOracleEncryptBlockSize(t *testing.T) {
+	oracle, err := NewAesCbcPaddingOracle([]byte("YELLOW SUBMARINE"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := PaddingOracleEncrypt(oracle, []byte("x"), 0, NewSeededRand(1)); !errors.Is(err, ErrBadBlockSize) {
+		t.Fatalf("Expected %v, got %v", ErrBadBlockSize, err)
+	}
+	_, _, queries, err := PaddingOracleEncrypt(oracle, []byte("x"), 8, NewSeededRand(1))
+	if !errors.Is(err, ErrBadOracle) || queries != 256 {
+		t.Fatalf("Expected %v after 256 queries, got %v after %d", ErrBadOracle, err, queries)
+	}
+}
+
+// cookieService is the service of challenge 16: it hands out encrypted cookies with escaped user
+// data, and tells apart the cookies it can't decrypt from the ones that just aren't an admin's.
+type cookieService struct {
+	key []byte
+	rng Rand
+}
+
+func (s cookieService) issue(userdata string) (iv []byte, ciphertext []byte, err error) {
+	cookie, err := EncodeKV([]KV{
+		{Key: "comment1", Value: "cooking MCs"},
+		{Key: "userdata", Value: userdata},
+		{Key: "comment2", Value: " like a pound of bacon"},
+	}, KVOptions{PairSeparator: ';', Escape: true})
+	if err != nil {
+		return nil, nil, err
+	}
+	iv = RandBytesFrom(s.rng, AesBlockSize)
+	ciphertext, err = AesCbcEncrypt([]byte(cookie), s.key, iv)
+	return iv, ciphertext, err
+}
+
+func (s cookieService) isAdmin(iv []byte, ciphertext []byte) (bool, error) {
+	decrypted, err := AesCbcDecryptWithPadding(ciphertext, s.key, iv, PKCS7)
+	if err != nil {
+		return false, err
+	}
+	cookie, err := ParseKVWithOptions(string(decrypted), KVOptions{PairSeparator: ';', Escape: true})
+	return err == nil && cookie["admin"] == "true", nil
+}
+
+func TestPaddingOracleEncryptForgesCookie(t *testing.T) {
+	rng := NewSeededRand(16)
+	service := cookieService{RandBytesFrom(rng, AesBlockSize), rng}
+	iv, ciphertext, err := service.issue(";admin=true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if admin, err := service.isAdmin(iv, ciphertext); err != nil || admin {
+		t.Fatalf("Expected an escaped cookie without admin rights, got %v and %v", admin, err)
+	}
+
+	// The only thing the attacker sees is whether the service could decrypt the cookie.
+	oracle := func(iv []byte, ciphertext []byte) bool {
+		_, err := service.isAdmin(iv, ciphertext)
+		return !errors.Is(err, ErrBadPaddingValue) && !errors.Is(err, ErrBadPaddingBytes)
+	}
+	forgedIV, forged, _, err := PaddingOracleEncrypt(oracle, []byte("comment1=cooking%20MCs;admin=true"), AesBlockSize, rng)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if admin, err := service.isAdmin(forgedIV, forged); err != nil || !admin {
+		t.Fatalf("Expected a forged admin cookie, got %v and %v", admin, err)
+	}
+}
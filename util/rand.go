@@ -0,0 +1,71 @@
+package util
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	mathrand "math/rand"
+)
+
+// Rand is the source of randomness for util and the oracles. Use CryptoRand for normal
+// runs, and NewSeededRand to make a run reproducible.
+type Rand interface {
+	io.Reader
+	// Intn returns a uniform random number in [0, n). It panics if n <= 0.
+	Intn(n int) int
+	Uint32() uint32
+}
+
+type cryptoRand struct{}
+
+var CryptoRand Rand = cryptoRand{}
+
+func (cryptoRand) Read(p []byte) (int, error) {
+	return rand.Read(p)
+}
+
+func (r cryptoRand) Uint32() uint32 {
+	return binary.LittleEndian.Uint32(RandBytesFrom(r, 4))
+}
+
+func (r cryptoRand) Intn(n int) int {
+	if n <= 0 {
+		panic("util: invalid argument to Intn")
+	}
+	// Reject the values from the incomplete last range to avoid the modulo bias.
+	limit := ^uint64(0) - ^uint64(0)%uint64(n)
+	for {
+		x := binary.LittleEndian.Uint64(RandBytesFrom(r, 8))
+		if x < limit {
+			return int(x % uint64(n))
+		}
+	}
+}
+
+// NewSeededRand is deterministic and must not be used for anything but tests and replays.
+func NewSeededRand(seed int64) Rand {
+	return mathrand.New(mathrand.NewSource(seed))
+}
+
+func ReadRandBytes(rng Rand, n int) ([]byte, error) {
+	res := make([]byte, n)
+	if _, err := io.ReadFull(rng, res); err != nil {
+		return nil, fmt.Errorf("failed to generate %d random bytes: %w", n, err)
+	}
+	return res, nil
+}
+
+// RandBytesFrom is ReadRandBytes for the oracles, which have no way to return an error.
+// It panics if rng fails, and the challenge runner reports the panic as a failure.
+func RandBytesFrom(rng Rand, n int) []byte {
+	res, err := ReadRandBytes(rng, n)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+func RandBytes(n int) []byte {
+	return RandBytesFrom(CryptoRand, n)
+}
@@ -0,0 +1,62 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestSeededRand(t *testing.T) {
+	a, b := NewSeededRand(1337), NewSeededRand(1337)
+	if !bytes.Equal(RandBytesFrom(a, 32), RandBytesFrom(b, 32)) || a.Intn(1000) != b.Intn(1000) || a.Uint32() != b.Uint32() {
+		t.Fatalf("Seeded sources with the same seed diverged")
+	}
+
+	padded1, _ := NewIso10126(NewSeededRand(1)).Pad([]byte("ICE ICE BABY"), AesBlockSize)
+	padded2, _ := NewIso10126(NewSeededRand(1)).Pad([]byte("ICE ICE BABY"), AesBlockSize)
+	if !bytes.Equal(padded1, padded2) {
+		t.Fatalf("Expected the same padding, got %q and %q", padded1, padded2)
+	}
+}
+
+func TestCryptoRandIntn(t *testing.T) {
+	seen := make([]bool, 7)
+	for i := 0; i < 1000; i++ {
+		x := CryptoRand.Intn(len(seen))
+		if x < 0 || x >= len(seen) {
+			t.Fatalf("Intn(%d) = %d", len(seen), x)
+		}
+		seen[x] = true
+	}
+	for x, ok := range seen {
+		if !ok {
+			t.Fatalf("Intn(%d) never returned %d", len(seen), x)
+		}
+	}
+}
+
+// failingRand runs out of randomness immediately.
+type failingRand struct {
+	Rand
+}
+
+func (failingRand) Read(p []byte) (int, error) {
+	return 0, io.ErrUnexpectedEOF
+}
+
+func TestReadRandBytesError(t *testing.T) {
+	rng := failingRand{NewSeededRand(1)}
+	if _, err := ReadRandBytes(rng, 16); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("Expected %v, got %v", io.ErrUnexpectedEOF, err)
+	}
+	if _, err := NewIso10126(rng).Pad([]byte("ICE ICE BABY"), AesBlockSize); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("Expected %v, got %v", io.ErrUnexpectedEOF, err)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("Expected RandBytesFrom to panic")
+		}
+	}()
+	RandBytesFrom(rng, 16)
+}
@@ -0,0 +1,171 @@
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+)
+
+type Encoding int
+
+const (
+	// AutoEncoding picks hex if the data only has hex digits, then base64 if it decodes, and raw otherwise.
+	AutoEncoding Encoding = iota
+	HexEncoding
+	Base64Encoding
+	RawEncoding
+)
+
+func (e Encoding) String() string {
+	switch e {
+	case AutoEncoding:
+		return "auto"
+	case HexEncoding:
+		return "hex"
+	case Base64Encoding:
+		return "base64"
+	case RawEncoding:
+		return "raw"
+	}
+	return fmt.Sprintf("Encoding(%d)", int(e))
+}
+
+func isHex(data []byte) bool {
+	if len(data)%2 != 0 {
+		return false
+	}
+	for _, b := range data {
+		if !('0' <= b && b <= '9' || 'a' <= b && b <= 'f' || 'A' <= b && b <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+func DetectEncoding(data []byte) Encoding {
+	compact := bytes.Join(bytes.Fields(data), nil)
+	if len(compact) > 0 && isHex(compact) {
+		return HexEncoding
+	}
+	if len(compact) > 0 {
+		if _, err := base64.StdEncoding.DecodeString(string(compact)); err == nil {
+			return Base64Encoding
+		}
+	}
+	return RawEncoding
+}
+
+// Decode decodes data in the given encoding. Whitespace is ignored for hex and base64,
+// so a blob may be split across several lines.
+func Decode(data []byte, enc Encoding) ([]byte, error) {
+	if enc == AutoEncoding {
+		enc = DetectEncoding(data)
+	}
+	switch enc {
+	case HexEncoding:
+		return hex.DecodeString(string(bytes.Join(bytes.Fields(data), nil)))
+	case Base64Encoding:
+		return base64.StdEncoding.DecodeString(string(bytes.Join(bytes.Fields(data), nil)))
+	case RawEncoding:
+		return data, nil
+	}
+	return nil, fmt.Errorf("unknown encoding %v", enc)
+}
+
+// ReadBlob reads the whole file as a single encoded value.
+func ReadBlob(fileName string, enc Encoding) ([]byte, error) {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	res, err := Decode(content, enc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fileName, err)
+	}
+	return res, nil
+}
+
+type Record struct {
+	// Line is 1-based.
+	Line int
+	Data []byte
+}
+
+const maxRecordSize = 16 * 1024 * 1024
+
+// RecordScanner reads one encoded record per line, skipping blank lines.
+// With AutoEncoding, the encoding is detected on the first record and used for the rest.
+type RecordScanner struct {
+	scanner *bufio.Scanner
+	enc     Encoding
+	line    int
+	record  Record
+	err     error
+}
+
+func NewRecordScanner(r io.Reader, enc Encoding) *RecordScanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxRecordSize)
+	return &RecordScanner{scanner: scanner, enc: enc}
+}
+
+func (s *RecordScanner) Scan() bool {
+	if s.err != nil {
+		return false
+	}
+	for s.scanner.Scan() {
+		s.line++
+		line := bytes.TrimRight(s.scanner.Bytes(), "\r")
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if s.enc == AutoEncoding {
+			s.enc = DetectEncoding(line)
+		}
+		if s.enc != RawEncoding {
+			line = bytes.TrimSpace(line)
+		}
+		data, err := Decode(line, s.enc)
+		if err != nil {
+			s.err = fmt.Errorf("line %d: %w", s.line, err)
+			return false
+		}
+		s.record = Record{Line: s.line, Data: append([]byte(nil), data...)}
+		return true
+	}
+	if err := s.scanner.Err(); err != nil {
+		s.err = fmt.Errorf("line %d: %w", s.line+1, err)
+	}
+	return false
+}
+
+func (s *RecordScanner) Record() Record {
+	return s.record
+}
+
+func (s *RecordScanner) Err() error {
+	return s.err
+}
+
+// ReadRecords reads the whole file with a RecordScanner.
+func ReadRecords(fileName string, enc Encoding) ([]Record, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var res []Record
+	scanner := NewRecordScanner(f, enc)
+	for scanner.Scan() {
+		res = append(res, scanner.Record())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", fileName, err)
+	}
+	return res, nil
+}
@@ -0,0 +1,65 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRecordScanner(t *testing.T) {
+	tests := []struct {
+		input    string
+		enc      Encoding
+		expected []Record
+	}{
+		{"49434520\n\n  4943452042414259\r\n", AutoEncoding, []Record{
+			{1, []byte("ICE ")},
+			{3, []byte("ICE BABY")},
+		}},
+		{"SUNFIEJBQlk=\nWUVMTE9X\n", AutoEncoding, []Record{
+			{1, []byte("ICE BABY")},
+			{2, []byte("YELLOW")},
+		}},
+		{"ICE BABY\r\n\nYELLOW ", AutoEncoding, []Record{
+			{1, []byte("ICE BABY")},
+			{3, []byte("YELLOW ")},
+		}},
+		{"abcd\n", Base64Encoding, []Record{
+			{1, []byte{0x69, 0xb7, 0x1d}},
+		}},
+	}
+	for _, tt := range tests {
+		var actual []Record
+		scanner := NewRecordScanner(strings.NewReader(tt.input), tt.enc)
+		for scanner.Scan() {
+			actual = append(actual, scanner.Record())
+		}
+		if err := scanner.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(actual, tt.expected) {
+			t.Fatalf("%q: expected %q, got %q", tt.input, tt.expected, actual)
+		}
+	}
+
+	scanner := NewRecordScanner(strings.NewReader("4943\n\n49zz\n"), HexEncoding)
+	for scanner.Scan() {
+	}
+	if err := scanner.Err(); err == nil || !strings.HasPrefix(err.Error(), "line 3:") {
+		t.Fatalf("Expected an error on line 3, got %v", err)
+	}
+}
+
+func TestReadBlob(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "blob.txt")
+	os.WriteFile(fileName, []byte("SUNFIElD\nRSBCQUJZ\n"), 0o644)
+	actual, err := ReadBlob(fileName, AutoEncoding)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(actual) != "ICE ICE BABY" {
+		t.Fatalf("Expected %q, got %q", "ICE ICE BABY", actual)
+	}
+}
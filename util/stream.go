@@ -0,0 +1,185 @@
+package util
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+	"io"
+)
+
+const streamChunkSize = 32 * 1024
+
+// EncryptWriter encrypts everything written to it with a block mode and writes the
+// ciphertext to the underlying writer. The PKCS#7 padding is only added by Close.
+type EncryptWriter struct {
+	w      io.Writer
+	mode   cipher.BlockMode
+	buf    []byte
+	closed bool
+	// err is sticky: after a failed write the cipher state no longer matches the output.
+	err error
+}
+
+func newEncryptWriter(w io.Writer, mode cipher.BlockMode) *EncryptWriter {
+	return &EncryptWriter{w: w, mode: mode}
+}
+
+func NewCbcEncryptWriter(w io.Writer, block cipher.Block, iv []byte) (*EncryptWriter, error) {
+	if err := checkIV(iv, block.BlockSize()); err != nil {
+		return nil, err
+	}
+	return newEncryptWriter(w, newCbcEncrypter(block, iv)), nil
+}
+
+func NewEcbEncryptWriter(w io.Writer, block cipher.Block) *EncryptWriter {
+	return newEncryptWriter(w, ecbEncrypter{block})
+}
+
+func NewAesCbcEncryptWriter(w io.Writer, key []byte, iv []byte) (*EncryptWriter, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return NewCbcEncryptWriter(w, block, iv)
+}
+
+func NewAesEcbEncryptWriter(w io.Writer, key []byte) (*EncryptWriter, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return NewEcbEncryptWriter(w, block), nil
+}
+
+func (e *EncryptWriter) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, fmt.Errorf("write to a closed EncryptWriter")
+	}
+	if e.err != nil {
+		return 0, e.err
+	}
+	bs := e.mode.BlockSize()
+	buffered := len(e.buf)
+	e.buf = append(e.buf, p...)
+	full := len(e.buf) / bs * bs
+	if full > 0 {
+		e.mode.CryptBlocks(e.buf[:full], e.buf[:full])
+		if written, err := e.w.Write(e.buf[:full]); err != nil {
+			e.err = err
+			// Only the bytes of p that made it to the underlying writer count as consumed.
+			n := written - buffered
+			if n < 0 {
+				n = 0
+			}
+			return n, err
+		}
+		e.buf = append(e.buf[:0], e.buf[full:]...)
+	}
+	return len(p), nil
+}
+
+// Close pads and flushes the last block. It does not close the underlying writer.
+func (e *EncryptWriter) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	if e.err != nil {
+		return e.err
+	}
+	padded, err := PKCS7Pad(e.buf, e.mode.BlockSize())
+	if err != nil {
+		return err
+	}
+	e.mode.CryptBlocks(padded, padded)
+	_, err = e.w.Write(padded)
+	return err
+}
+
+// DecryptReader decrypts the ciphertext read from the underlying reader. The last block
+// is held back until the underlying reader is exhausted, so that the padding can be removed.
+type DecryptReader struct {
+	r     io.Reader
+	mode  cipher.BlockMode
+	chunk []byte
+	in    []byte
+	out   []byte
+	err   error
+}
+
+func newDecryptReader(r io.Reader, mode cipher.BlockMode) *DecryptReader {
+	return &DecryptReader{r: r, mode: mode, chunk: make([]byte, streamChunkSize)}
+}
+
+func NewCbcDecryptReader(r io.Reader, block cipher.Block, iv []byte) (*DecryptReader, error) {
+	if err := checkIV(iv, block.BlockSize()); err != nil {
+		return nil, err
+	}
+	return newDecryptReader(r, newCbcDecrypter(block, iv)), nil
+}
+
+func NewEcbDecryptReader(r io.Reader, block cipher.Block) *DecryptReader {
+	return newDecryptReader(r, ecbDecrypter{block})
+}
+
+func NewAesCbcDecryptReader(r io.Reader, key []byte, iv []byte) (*DecryptReader, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return NewCbcDecryptReader(r, block, iv)
+}
+
+func NewAesEcbDecryptReader(r io.Reader, key []byte) (*DecryptReader, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return NewEcbDecryptReader(r, block), nil
+}
+
+func (d *DecryptReader) Read(p []byte) (int, error) {
+	for len(d.out) == 0 {
+		if d.err != nil {
+			return 0, d.err
+		}
+		d.fill()
+	}
+	n := copy(p, d.out)
+	d.out = d.out[n:]
+	return n, nil
+}
+
+func (d *DecryptReader) fill() {
+	n, err := d.r.Read(d.chunk)
+	d.in = append(d.in, d.chunk[:n]...)
+	bs := d.mode.BlockSize()
+
+	if err == io.EOF {
+		if err := checkLength(d.in, bs); err != nil {
+			d.err = err
+			return
+		}
+		d.mode.CryptBlocks(d.in, d.in)
+		d.out, d.err = PKCS7Unpad(d.in, bs)
+		d.in = nil
+		if d.err == nil {
+			d.err = io.EOF
+		}
+		return
+	}
+	if err != nil {
+		d.err = err
+		return
+	}
+
+	if len(d.in) == 0 {
+		return
+	}
+	ready := (len(d.in) - 1) / bs * bs
+	if ready > 0 {
+		d.mode.CryptBlocks(d.in[:ready], d.in[:ready])
+		d.out = append(d.out[:0], d.in[:ready]...)
+		d.in = append(d.in[:0], d.in[ready:]...)
+	}
+}
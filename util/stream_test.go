@@ -0,0 +1,119 @@
+package util
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestStreamMatchesOneShot(t *testing.T) {
+	key := []byte("YELLOW SUBMARINE")
+	iv := []byte("0123456789abcdef")
+	for _, size := range []int{0, 1, 15, 16, 17, 100, 5000} {
+		plaintext := make([]byte, size)
+		for i := range plaintext {
+			plaintext[i] = byte(i * 7)
+		}
+
+		expectedCbc, _ := AesCbcEncrypt(plaintext, key, iv)
+		expectedEcb, _ := AesEcbEncrypt(plaintext, key)
+
+		var cbcBuf, ecbBuf bytes.Buffer
+		cbcWriter, err := NewAesCbcEncryptWriter(&cbcBuf, key, iv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ecbWriter, err := NewAesEcbEncryptWriter(&ecbBuf, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Write in uneven pieces to check the buffering.
+		for i, step := 0, 1; i < len(plaintext); i, step = i+step, step+5 {
+			end := i + step
+			if end > len(plaintext) {
+				end = len(plaintext)
+			}
+			cbcWriter.Write(plaintext[i:end])
+			ecbWriter.Write(plaintext[i:end])
+		}
+		if err := cbcWriter.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := ecbWriter.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(cbcBuf.Bytes(), expectedCbc) {
+			t.Fatalf("CBC, %d bytes: expected %x, got %x", size, expectedCbc, cbcBuf.Bytes())
+		}
+		if !bytes.Equal(ecbBuf.Bytes(), expectedEcb) {
+			t.Fatalf("ECB, %d bytes: expected %x, got %x", size, expectedEcb, ecbBuf.Bytes())
+		}
+
+		cbcReader, err := NewAesCbcDecryptReader(iotest.OneByteReader(bytes.NewReader(expectedCbc)), key, iv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ecbReader, err := NewAesEcbDecryptReader(iotest.HalfReader(bytes.NewReader(expectedEcb)), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range []io.Reader{cbcReader, ecbReader} {
+			actual, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(actual, plaintext) {
+				t.Fatalf("%d bytes: expected %x, got %x", size, plaintext, actual)
+			}
+		}
+	}
+}
+
+func TestDecryptReaderBadInput(t *testing.T) {
+	key := []byte("YELLOW SUBMARINE")
+	good, _ := AesEcbEncrypt([]byte("ICE ICE BABY"), key)
+	badTests := [][]byte{
+		{},
+		good[:len(good)-1],
+		append(good, 0),
+	}
+	for _, tt := range badTests {
+		r, _ := NewAesEcbDecryptReader(bytes.NewReader(tt), key)
+		if actual, err := io.ReadAll(r); err == nil {
+			t.Fatalf("Expected an error, got %q", actual)
+		}
+	}
+}
+
+// shortWriter accepts limit bytes and fails after that.
+type shortWriter struct {
+	limit int
+}
+
+func (w *shortWriter) Write(p []byte) (int, error) {
+	if len(p) > w.limit {
+		n := w.limit
+		w.limit = 0
+		return n, io.ErrShortWrite
+	}
+	w.limit -= len(p)
+	return len(p), nil
+}
+
+func TestEncryptWriterPartialWrite(t *testing.T) {
+	w, _ := NewAesEcbEncryptWriter(&shortWriter{limit: 20}, []byte("YELLOW SUBMARINE"))
+	if n, err := w.Write(make([]byte, 10)); n != 10 || err != nil {
+		t.Fatalf("Expected 10 bytes and no error, got %d and %v", n, err)
+	}
+	// 32 bytes go to the underlying writer, which only takes 20, 10 of which were buffered before.
+	if n, err := w.Write(make([]byte, 30)); n != 10 || err != io.ErrShortWrite {
+		t.Fatalf("Expected 10 bytes and %v, got %d and %v", io.ErrShortWrite, n, err)
+	}
+	if n, err := w.Write(make([]byte, 1)); n != 0 || err != io.ErrShortWrite {
+		t.Fatalf("Expected 0 bytes and %v, got %d and %v", io.ErrShortWrite, n, err)
+	}
+	if err := w.Close(); err != io.ErrShortWrite {
+		t.Fatalf("Expected %v, got %v", io.ErrShortWrite, err)
+	}
+}
@@ -2,38 +2,33 @@ package util
 
 import (
 	"crypto/aes"
-	"crypto/rand"
-	"encoding/base64"
-	"fmt"
-	"log"
-	"os"
-	"strings"
+	"crypto/cipher"
 )
 
-func PKCS7Pad(input []byte, blockSize int) []byte {
+func PKCS7Pad(input []byte, blockSize int) ([]byte, error) {
+	if err := checkBlockSize(blockSize); err != nil {
+		return nil, err
+	}
+
 	rem := blockSize - len(input)%blockSize
 	res := make([]byte, len(input)+rem)
 	copy(res, input)
 	for i := len(input); i < len(res); i++ {
 		res[i] = byte(rem)
 	}
-	return res
+	return res, nil
 }
 
 func PKCS7Unpad(input []byte, blockSize int) ([]byte, error) {
-	if len(input) == 0 || len(input)%blockSize != 0 {
-		return nil, fmt.Errorf("invalid input length for block size %d: %d", blockSize, len(input))
-	}
-
-	nPad := int(input[len(input)-1])
-	if nPad > blockSize {
-		return nil, fmt.Errorf("the padding %d is too large for block size %d", nPad, blockSize)
+	nPad, err := unpadWithLength(input, blockSize)
+	if err != nil {
+		return nil, err
 	}
 
 	for i := 2; i <= nPad; i++ {
-		curPad := int(input[len(input)-i])
-		if curPad != nPad {
-			return nil, fmt.Errorf("invalid padding at position %d: got %d, expected %d", i, curPad, nPad)
+		curPad := input[len(input)-i]
+		if int(curPad) != nPad {
+			return nil, &PaddingError{Err: ErrBadPaddingBytes, Position: i, Got: curPad, Expected: byte(nPad)}
 		}
 	}
 
@@ -42,123 +37,118 @@ func PKCS7Unpad(input []byte, blockSize int) ([]byte, error) {
 
 const AesBlockSize = 16
 
-func AesCbcDecrypt(ciphertext []byte, key []byte, iv []byte) (res []byte, err error) {
-	if len(iv) != AesBlockSize {
-		return nil, fmt.Errorf("IV had %d bytes, expected %d", len(iv), AesBlockSize)
+func CbcDecryptWithPadding(block cipher.Block, ciphertext []byte, iv []byte, padding Padding) ([]byte, error) {
+	bs := block.BlockSize()
+	if err := checkIV(iv, bs); err != nil {
+		return nil, err
 	}
-
-	cipher, err := aes.NewCipher(key)
-	if err != nil {
+	if err := checkLength(ciphertext, bs); err != nil {
 		return nil, err
 	}
 
-	res = make([]byte, len(ciphertext))
-	prevBlock := iv
-	for i := 0; i < len(ciphertext); i += AesBlockSize {
-		curBlock := ciphertext[i : i+AesBlockSize]
-		cipher.Decrypt(res[i:i+AesBlockSize], curBlock)
-		for j := 0; j < AesBlockSize; j++ {
-			res[i+j] ^= prevBlock[j]
-		}
-		prevBlock = curBlock
-	}
-	return res, nil
+	res := make([]byte, len(ciphertext))
+	newCbcDecrypter(block, iv).CryptBlocks(res, ciphertext)
+	return padding.Unpad(res, bs)
 }
 
-func AesCbcEncrypt(plaintext []byte, key []byte, iv []byte) (res []byte, err error) {
-	if len(iv) != AesBlockSize {
-		return nil, fmt.Errorf("IV had %d bytes, expected %d", len(iv), AesBlockSize)
+func CbcEncryptWithPadding(block cipher.Block, plaintext []byte, iv []byte, padding Padding) ([]byte, error) {
+	bs := block.BlockSize()
+	if err := checkIV(iv, bs); err != nil {
+		return nil, err
 	}
 
-	cipher, err := aes.NewCipher(key)
+	res, err := padding.Pad(plaintext, bs)
 	if err != nil {
 		return nil, err
 	}
-
-	padded := PKCS7Pad(plaintext, AesBlockSize)
-	res = make([]byte, len(padded))
-	prevBlock := iv
-	tmpBuf := make([]byte, AesBlockSize)
-	for i := 0; i < len(padded); i += AesBlockSize {
-		for j := 0; j < AesBlockSize; j++ {
-			tmpBuf[j] = padded[i+j] ^ prevBlock[j]
-		}
-		prevBlock = res[i : i+AesBlockSize]
-		cipher.Encrypt(prevBlock, tmpBuf)
-	}
-
+	newCbcEncrypter(block, iv).CryptBlocks(res, res)
 	return res, nil
 }
 
-func AesEcbEncrypt(plaintext []byte, key []byte) (res []byte, err error) {
-	cipher, err := aes.NewCipher(key)
+func EcbEncryptWithPadding(block cipher.Block, plaintext []byte, padding Padding) ([]byte, error) {
+	res, err := padding.Pad(plaintext, block.BlockSize())
 	if err != nil {
 		return nil, err
 	}
-
-	padded := PKCS7Pad(plaintext, AesBlockSize)
-	res = make([]byte, len(padded))
-	for i := 0; i < len(res); i += AesBlockSize {
-		cipher.Encrypt(res[i:i+AesBlockSize], padded[i:i+AesBlockSize])
-	}
+	ecbEncrypter{block}.CryptBlocks(res, res)
 	return res, nil
 }
 
-func AesEcbDecrypt(ciphertext []byte, key []byte) ([]byte, error) {
-	cipher, err := aes.NewCipher(key)
-	if err != nil {
+func EcbDecryptWithPadding(block cipher.Block, ciphertext []byte, padding Padding) ([]byte, error) {
+	if err := checkLength(ciphertext, block.BlockSize()); err != nil {
 		return nil, err
 	}
 
 	res := make([]byte, len(ciphertext))
-	for i := 0; i < len(res); i += AesBlockSize {
-		cipher.Decrypt(res[i:i+AesBlockSize], ciphertext[i:i+AesBlockSize])
-	}
-	return PKCS7Unpad(res, AesBlockSize)
+	ecbDecrypter{block}.CryptBlocks(res, ciphertext)
+	return padding.Unpad(res, block.BlockSize())
 }
 
-func ReadBase64File(fileName string) (content []byte, err error) {
-	b64content, err := os.ReadFile(fileName)
+// CbcDecrypt leaves the padding in place, use CbcDecryptWithPadding to remove it.
+func CbcDecrypt(block cipher.Block, ciphertext []byte, iv []byte) ([]byte, error) {
+	return CbcDecryptWithPadding(block, ciphertext, iv, NoPadding)
+}
+
+func CbcEncrypt(block cipher.Block, plaintext []byte, iv []byte) ([]byte, error) {
+	return CbcEncryptWithPadding(block, plaintext, iv, PKCS7)
+}
+
+func EcbEncrypt(block cipher.Block, plaintext []byte) ([]byte, error) {
+	return EcbEncryptWithPadding(block, plaintext, PKCS7)
+}
+
+func EcbDecrypt(block cipher.Block, ciphertext []byte) ([]byte, error) {
+	return EcbDecryptWithPadding(block, ciphertext, PKCS7)
+}
+
+func AesCbcDecryptWithPadding(ciphertext []byte, key []byte, iv []byte, padding Padding) ([]byte, error) {
+	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
 	}
+	return CbcDecryptWithPadding(block, ciphertext, iv, padding)
+}
 
-	res := make([]byte, base64.StdEncoding.DecodedLen(len(b64content)))
-	bytesDecoded, err := base64.StdEncoding.Decode(res, b64content)
+func AesCbcEncryptWithPadding(plaintext []byte, key []byte, iv []byte, padding Padding) ([]byte, error) {
+	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
 	}
-
-	return res[:bytesDecoded], nil
+	return CbcEncryptWithPadding(block, plaintext, iv, padding)
 }
 
-func RandBytes(n int) []byte {
-	res := make([]byte, n)
-	_, err := rand.Read(res)
+func AesEcbEncryptWithPadding(plaintext []byte, key []byte, padding Padding) ([]byte, error) {
+	block, err := aes.NewCipher(key)
 	if err != nil {
-		log.Fatalf("Failed to generated %d random bytes: %v", n, err)
+		return nil, err
 	}
-	return res
-}
-
-func ParseKV(s string) (map[string]string, error) {
-	res := make(map[string]string)
-	hasMoreKV := true
-	for hasMoreKV {
-		kv := s
-		hasMoreKV = false
-		if ampPos := strings.Index(s, "&"); ampPos >= 0 {
-			kv = s[:ampPos]
-			s = s[ampPos+1:]
-			hasMoreKV = true
-		}
+	return EcbEncryptWithPadding(block, plaintext, padding)
+}
 
-		if eqPos := strings.Index(kv, "="); eqPos >= 0 {
-			key, value := kv[:eqPos], kv[eqPos+1:]
-			res[key] = value
-		} else {
-			return nil, fmt.Errorf("<%s> is not a valid key/value pair", kv)
-		}
+func AesEcbDecryptWithPadding(ciphertext []byte, key []byte, padding Padding) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
 	}
-	return res, nil
+	return EcbDecryptWithPadding(block, ciphertext, padding)
+}
+
+func AesCbcDecrypt(ciphertext []byte, key []byte, iv []byte) ([]byte, error) {
+	return AesCbcDecryptWithPadding(ciphertext, key, iv, NoPadding)
+}
+
+func AesCbcEncrypt(plaintext []byte, key []byte, iv []byte) ([]byte, error) {
+	return AesCbcEncryptWithPadding(plaintext, key, iv, PKCS7)
+}
+
+func AesEcbEncrypt(plaintext []byte, key []byte) ([]byte, error) {
+	return AesEcbEncryptWithPadding(plaintext, key, PKCS7)
+}
+
+func AesEcbDecrypt(ciphertext []byte, key []byte) ([]byte, error) {
+	return AesEcbDecryptWithPadding(ciphertext, key, PKCS7)
+}
+
+func ReadBase64File(fileName string) (content []byte, err error) {
+	return ReadBlob(fileName, Base64Encoding)
 }
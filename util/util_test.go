@@ -1,49 +1,92 @@
 package util
 
 import (
-	"reflect"
+	"bytes"
+	"crypto/cipher"
+	"crypto/des"
+	"errors"
 	"testing"
 )
 
-func TestParseKV(t *testing.T) {
-	goodTests := []struct {
+func TestBlockModesWithDes(t *testing.T) {
+	block, err := des.NewTripleDESCipher([]byte("YELLOW SUBMARINE ICE ICE"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	iv := []byte("8 bytes!")
+	plaintext := []byte("Burning 'em, if you ain't quick and nimble")
+
+	padded, _ := PKCS7Pad(plaintext, block.BlockSize())
+	expected := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(expected, padded)
+	actual, err := CbcEncrypt(block, plaintext, iv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected, actual) {
+		t.Fatalf("Expected %x, got %x", expected, actual)
+	}
+	decrypted, err := CbcDecrypt(block, actual, iv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(padded, decrypted) {
+		t.Fatalf("Expected %q, got %q", padded, decrypted)
+	}
+
+	encrypted, err := EcbEncrypt(block, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(encrypted) != 48 {
+		t.Fatalf("Expected 48 bytes of ECB ciphertext, got %d", len(encrypted))
+	}
+	decrypted, err = EcbDecrypt(block, encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(plaintext, decrypted) {
+		t.Fatalf("Expected %q, got %q", plaintext, decrypted)
+	}
+}
+
+func TestPaddingErrors(t *testing.T) {
+	if _, err := PKCS7Pad([]byte("abc"), 256); !errors.Is(err, ErrBadBlockSize) {
+		t.Fatalf("Expected ErrBadBlockSize, got %v", err)
+	}
+
+	unpadTests := []struct {
 		input    string
-		expected map[string]string
+		expected error
 	}{
-		{"lol=kek", map[string]string{
-			"lol": "kek",
-		}},
-		{"foo=bar&baz=qux&zap=zazzle", map[string]string{
-			"foo": "bar",
-			"baz": "qux",
-			"zap": "zazzle",
-		}},
-		{"=&role==admin&user=", map[string]string{
-			"":     "",
-			"role": "=admin",
-			"user": "",
-		}},
-	}
-	for _, tt := range goodTests {
-		actual, err := ParseKV(tt.input)
-		if err != nil {
-			t.Fatalf("Expected %v, got error %v", tt.expected, err)
-		}
-		if !reflect.DeepEqual(tt.expected, actual) {
-			t.Fatalf("Expected %v, got %v", tt.expected, actual)
+		{"", ErrBadLength},
+		{"ICE ICE BABY\x04\x04\x04", ErrBadLength},
+		{"ICE ICE BABY\x04\x04\x04\x00", ErrBadPaddingValue},
+		{"ICE ICE BABY\x04\x04\x04\x11", ErrBadPaddingValue},
+		{"ICE ICE BABY\x05\x05\x05\x05", ErrBadPaddingBytes},
+		{"ICE ICE BABY\x01\x02\x03\x04", ErrBadPaddingBytes},
+	}
+	for _, tt := range unpadTests {
+		_, err := PKCS7Unpad([]byte(tt.input), AesBlockSize)
+		if !errors.Is(err, tt.expected) {
+			t.Fatalf("PKCS7Unpad(%q): expected %v, got %v", tt.input, tt.expected, err)
 		}
 	}
 
-	badTests := []string{
-		"&",
-		"role=admin&&user=foo",
-		"blah=&",
-		"abc&def=ghi",
+	var paddingErr *PaddingError
+	_, err := PKCS7Unpad([]byte("ICE ICE BABY\x01\x02\x03\x04"), AesBlockSize)
+	if !errors.As(err, &paddingErr) || paddingErr.Position != 2 || paddingErr.Got != 3 {
+		t.Fatalf("Expected a padding error at position 2, got %v", err)
 	}
-	for _, tt := range badTests {
-		actual, err := ParseKV(tt)
-		if err == nil {
-			t.Fatalf("Expected an error, got %v", actual)
-		}
+
+	key := []byte("YELLOW SUBMARINE")
+	if _, err := AesCbcDecrypt(make([]byte, 17), key, make([]byte, AesBlockSize)); !errors.Is(err, ErrBadLength) {
+		t.Fatalf("Expected ErrBadLength, got %v", err)
+	}
+	if _, err := AesCbcDecrypt(make([]byte, 16), key, make([]byte, 8)); !errors.Is(err, ErrBadIV) {
+		t.Fatalf("Expected ErrBadIV, got %v", err)
+	}
+	if _, err := AesEcbDecrypt(make([]byte, 31), key); !errors.Is(err, ErrBadLength) {
+		t.Fatalf("Expected ErrBadLength, got %v", err)
 	}
 }
@@ -0,0 +1,110 @@
+package xor
+
+import (
+	"container/heap"
+	"cryptopals/score"
+	"cryptopals/util"
+	"io"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+type DetectOptions struct {
+	// Workers break records in parallel, runtime.NumCPU() by default.
+	Workers int
+	// TopK best detections are kept, 10 by default.
+	TopK int
+	// Scorer must be safe for concurrent use, ChiSquared by default.
+	Scorer   score.Scorer
+	Encoding util.Encoding
+}
+
+type Detection struct {
+	// Line is 1-based, as in util.Record.
+	Line      int
+	Key       byte
+	Plaintext []byte
+	Score     float64
+}
+
+func detectionLess(a Detection, b Detection) bool {
+	if a.Score != b.Score {
+		return a.Score < b.Score
+	}
+	return a.Line < b.Line
+}
+
+// detectionHeap keeps the worst of the best detections on top, so that it can be evicted.
+type detectionHeap []Detection
+
+func (h detectionHeap) Len() int           { return len(h) }
+func (h detectionHeap) Less(i, j int) bool { return detectionLess(h[j], h[i]) }
+func (h detectionHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *detectionHeap) Push(x any)        { *h = append(*h, x.(Detection)) }
+func (h *detectionHeap) Pop() any {
+	old := *h
+	res := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return res
+}
+
+// DetectSingleByte streams records from r, breaks every one of them as a single-byte XOR
+// and returns the TopK records that decrypt to the best-scoring plaintexts, best first.
+func DetectSingleByte(r io.Reader, opts DetectOptions) ([]Detection, error) {
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	if opts.TopK <= 0 {
+		opts.TopK = 10
+	}
+	if opts.Scorer == nil {
+		opts.Scorer = score.ChiSquared
+	}
+
+	records := make(chan util.Record, opts.Workers)
+	detections := make(chan Detection, opts.Workers)
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for record := range records {
+				res := BreakSingleByte(record.Data, opts.Scorer)
+				detections <- Detection{Line: record.Line, Key: res.Key, Plaintext: res.Plaintext, Score: res.Score}
+			}
+		}()
+	}
+
+	top := &detectionHeap{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for detection := range detections {
+			if top.Len() < opts.TopK {
+				heap.Push(top, detection)
+			} else if detectionLess(detection, (*top)[0]) {
+				(*top)[0] = detection
+				heap.Fix(top, 0)
+			}
+		}
+	}()
+
+	scanner := util.NewRecordScanner(r, opts.Encoding)
+	for scanner.Scan() {
+		records <- scanner.Record()
+	}
+	close(records)
+	wg.Wait()
+	close(detections)
+	<-done
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	res := []Detection(*top)
+	sort.Slice(res, func(i, j int) bool {
+		return detectionLess(res[i], res[j])
+	})
+	return res, nil
+}
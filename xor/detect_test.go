@@ -0,0 +1,44 @@
+package xor
+
+import (
+	"cryptopals/util"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestDetectSingleByte(t *testing.T) {
+	rng := util.NewSeededRand(1)
+	var input strings.Builder
+	for i := 1; i <= 200; i++ {
+		line := util.RandBytesFrom(rng, 30)
+		if i == 123 {
+			line = RepeatingKey([]byte("Now that the party is jumping\n"), []byte{53})
+		}
+		input.WriteString(hex.EncodeToString(line) + "\n")
+	}
+
+	detections, err := DetectSingleByte(strings.NewReader(input.String()), DetectOptions{Workers: 4, TopK: 3, Encoding: util.HexEncoding})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(detections) != 3 {
+		t.Fatalf("Expected %v detections, got %v", 3, len(detections))
+	}
+	best := detections[0]
+	if best.Line != 123 || best.Key != 53 || string(best.Plaintext) != "Now that the party is jumping\n" {
+		t.Fatalf("Expected line %v with key %v, got %+v", 123, 53, best)
+	}
+	for i := 1; i < len(detections); i++ {
+		if detections[i].Score < detections[i-1].Score {
+			t.Fatalf("Expected sorted detections, got %+v", detections)
+		}
+	}
+}
+
+func TestDetectSingleByteError(t *testing.T) {
+	_, err := DetectSingleByte(strings.NewReader("abcd\nnot hex\n"), DetectOptions{Encoding: util.HexEncoding})
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("Expected an error on line 2, got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package xor
+
+import (
+	"math/bits"
+	"sort"
+)
+
+type KeySizeMethod int
+
+const (
+	// Coincidence counts the bytes equal to the byte keySize positions later. This is what
+	// challenge 6 originally used: it favours key sizes with many positions to compare.
+	Coincidence KeySizeMethod = iota
+	// HammingDistance averages the bit distance between the first few blocks, normalized by the key size.
+	HammingDistance
+	// IndexOfCoincidence averages the index of coincidence of the columns of the ciphertext.
+	IndexOfCoincidence
+)
+
+// Blocks compared by HammingDistance.
+const hammingBlocks = 8
+
+type KeySizeCandidate struct {
+	KeySize int
+	// Lower is better. Scores are only comparable between candidates ranked with the same method.
+	Score float64
+}
+
+func Hamming(a []byte, b []byte) int {
+	res := 0
+	for i := range a {
+		res += bits.OnesCount8(a[i] ^ b[i])
+	}
+	return res
+}
+
+func coincidenceScore(ciphertext []byte, keySize int) float64 {
+	similarity := 0
+	for i := 0; i+keySize < len(ciphertext); i++ {
+		if ciphertext[i] == ciphertext[i+keySize] {
+			similarity++
+		}
+	}
+	return -float64(similarity)
+}
+
+func hammingScore(ciphertext []byte, keySize int) float64 {
+	blocks := len(ciphertext) / keySize
+	if blocks > hammingBlocks {
+		blocks = hammingBlocks
+	}
+	total, pairs := 0, 0
+	for i := 0; i < blocks; i++ {
+		for j := i + 1; j < blocks; j++ {
+			total += Hamming(ciphertext[i*keySize:(i+1)*keySize], ciphertext[j*keySize:(j+1)*keySize])
+			pairs++
+		}
+	}
+	if pairs == 0 {
+		return 8
+	}
+	return float64(total) / float64(pairs) / float64(keySize)
+}
+
+func indexOfCoincidenceScore(ciphertext []byte, keySize int) float64 {
+	sum := 0.0
+	for column := 0; column < keySize; column++ {
+		var counts [256]int
+		n := 0
+		for i := column; i < len(ciphertext); i += keySize {
+			counts[ciphertext[i]]++
+			n++
+		}
+		if n < 2 {
+			continue
+		}
+		coincidences := 0
+		for _, c := range counts {
+			coincidences += c * (c - 1)
+		}
+		sum += float64(coincidences) / float64(n*(n-1))
+	}
+	return -sum / float64(keySize)
+}
+
+// RankKeySizes scores every key size in [minSize, maxSize] and returns them best first.
+func RankKeySizes(ciphertext []byte, minSize int, maxSize int, method KeySizeMethod) []KeySizeCandidate {
+	if minSize < 1 {
+		minSize = 1
+	}
+	if maxSize > len(ciphertext) {
+		maxSize = len(ciphertext)
+	}
+
+	var res []KeySizeCandidate
+	for keySize := minSize; keySize <= maxSize; keySize++ {
+		var score float64
+		switch method {
+		case HammingDistance:
+			score = hammingScore(ciphertext, keySize)
+		case IndexOfCoincidence:
+			score = indexOfCoincidenceScore(ciphertext, keySize)
+		default:
+			score = coincidenceScore(ciphertext, keySize)
+		}
+		res = append(res, KeySizeCandidate{keySize, score})
+	}
+	// On ties, the smaller key size wins, since its multiples score just as well.
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Score < res[j].Score
+	})
+	return res
+}
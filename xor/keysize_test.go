@@ -0,0 +1,43 @@
+package xor
+
+import (
+	"strings"
+	"testing"
+)
+
+const testPlaintext = `It was the best of times, it was the worst of times, it was the age of wisdom,
+it was the age of foolishness, it was the epoch of belief, it was the epoch of incredulity,
+it was the season of Light, it was the season of Darkness, it was the spring of hope,
+it was the winter of despair, we had everything before us, we had nothing before us,
+we were all going direct to Heaven, we were all going direct the other way.`
+
+func TestHamming(t *testing.T) {
+	if d := Hamming([]byte("this is a test"), []byte("wokka wokka!!!")); d != 37 {
+		t.Fatalf("Expected %v, got %v", 37, d)
+	}
+}
+
+func TestRankKeySizes(t *testing.T) {
+	key := []byte("Lorem ipsum")
+	ciphertext := RepeatingKey([]byte(strings.Repeat(testPlaintext, 2)), key)
+	for _, method := range []KeySizeMethod{Coincidence, HammingDistance, IndexOfCoincidence} {
+		ranked := RankKeySizes(ciphertext, 2, 40, method)
+		if len(ranked) != 39 {
+			t.Fatalf("Expected %v candidates with method %v, got %v", 39, method, len(ranked))
+		}
+		for i := 1; i < len(ranked); i++ {
+			if ranked[i].Score < ranked[i-1].Score {
+				t.Fatalf("Expected sorted candidates with method %v, got %v", method, ranked)
+			}
+		}
+		found := false
+		for _, candidate := range ranked[:3] {
+			if candidate.KeySize%len(key) == 0 {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("Expected a multiple of %v in the top 3 with method %v, got %v", len(key), method, ranked[:3])
+		}
+	}
+}
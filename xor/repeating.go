@@ -0,0 +1,95 @@
+package xor
+
+import (
+	"cryptopals/score"
+	"fmt"
+	"sort"
+)
+
+type Options struct {
+	// Key sizes to consider, 2 to 40 by default.
+	MinKeySize, MaxKeySize int
+	Method                 KeySizeMethod
+	// ColumnScorer breaks every column as a single-byte XOR, ChiSquared by default.
+	// The columns are not contiguous text, so scorers based on n-grams are of no use here.
+	ColumnScorer score.Scorer
+	// Scorer ranks the whole plaintexts, so it can be a language model. ChiSquared by default.
+	Scorer score.Scorer
+	// TopN best-ranked key sizes are broken, 3 by default.
+	TopN int
+}
+
+func (opts Options) withDefaults() Options {
+	if opts.MinKeySize <= 0 {
+		opts.MinKeySize = 2
+	}
+	if opts.MaxKeySize <= 0 {
+		opts.MaxKeySize = 40
+	}
+	if opts.ColumnScorer == nil {
+		opts.ColumnScorer = score.ChiSquared
+	}
+	if opts.Scorer == nil {
+		opts.Scorer = score.ChiSquared
+	}
+	if opts.TopN <= 0 {
+		opts.TopN = 3
+	}
+	return opts
+}
+
+type Candidate struct {
+	Key       []byte
+	Plaintext []byte
+	Score     float64
+	// Confidence of every key byte, as in SingleByteResult.
+	Confidence []float64
+}
+
+// BreakRepeatingKeyWithSize solves every column of the ciphertext as a single-byte XOR.
+func BreakRepeatingKeyWithSize(ciphertext []byte, keySize int, scorer score.Scorer) Candidate {
+	res := Candidate{Key: make([]byte, keySize), Confidence: make([]float64, keySize)}
+	column := make([]byte, 0, len(ciphertext)/keySize+1)
+	for i := 0; i < keySize; i++ {
+		column = column[:0]
+		for j := i; j < len(ciphertext); j += keySize {
+			column = append(column, ciphertext[j])
+		}
+		single := BreakSingleByte(column, scorer)
+		res.Key[i], res.Confidence[i] = single.Key, single.Confidence
+	}
+	res.Plaintext = RepeatingKey(ciphertext, res.Key)
+	res.Score = scorer.Score(res.Plaintext)
+	return res
+}
+
+// BreakRepeatingKey breaks the ciphertext with the best-ranked key sizes and
+// returns the candidates best first.
+func BreakRepeatingKey(ciphertext []byte, opts Options) ([]Candidate, error) {
+	opts = opts.withDefaults()
+	if opts.MinKeySize > opts.MaxKeySize {
+		return nil, fmt.Errorf("empty key size range [%d, %d]", opts.MinKeySize, opts.MaxKeySize)
+	}
+	if len(ciphertext) < opts.MinKeySize {
+		return nil, fmt.Errorf("%d bytes of ciphertext are too few for keys of at least %d bytes", len(ciphertext), opts.MinKeySize)
+	}
+
+	sizes := RankKeySizes(ciphertext, opts.MinKeySize, opts.MaxKeySize, opts.Method)
+	if len(sizes) > opts.TopN {
+		sizes = sizes[:opts.TopN]
+	}
+	var res []Candidate
+	for _, size := range sizes {
+		candidate := BreakRepeatingKeyWithSize(ciphertext, size.KeySize, opts.ColumnScorer)
+		candidate.Score = opts.Scorer.Score(candidate.Plaintext)
+		res = append(res, candidate)
+	}
+	// Multiples of the key size give the same plaintext, so the shortest key wins a tie.
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Score != res[j].Score {
+			return res[i].Score < res[j].Score
+		}
+		return len(res[i].Key) < len(res[j].Key)
+	})
+	return res, nil
+}
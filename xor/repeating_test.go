@@ -0,0 +1,63 @@
+package xor
+
+import (
+	"bytes"
+	"cryptopals/score"
+	"testing"
+)
+
+func TestBreakSingleByte(t *testing.T) {
+	plaintext := []byte("Cooking MC's like a pound of bacon")
+	res := BreakSingleByte(RepeatingKey(plaintext, []byte{88}), score.ChiSquared)
+	if res.Key != 88 || !bytes.Equal(res.Plaintext, plaintext) {
+		t.Fatalf("Expected %v and %q, got %v and %q", 88, plaintext, res.Key, res.Plaintext)
+	}
+	if res.Confidence <= 0 {
+		t.Fatalf("Expected a positive confidence, got %v", res.Confidence)
+	}
+}
+
+func TestBreakRepeatingKey(t *testing.T) {
+	key := []byte("Lorem ipsum")
+	ciphertext := RepeatingKey([]byte(testPlaintext), key)
+	candidates, err := BreakRepeatingKey(ciphertext, Options{TopN: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) != 5 {
+		t.Fatalf("Expected %v candidates, got %v", 5, len(candidates))
+	}
+	best := candidates[0]
+	if !bytes.Equal(best.Key, key) || string(best.Plaintext) != testPlaintext {
+		t.Fatalf("Expected %q, got %q", key, best.Key)
+	}
+	if len(best.Confidence) != len(key) {
+		t.Fatalf("Expected %v confidences, got %v", len(key), len(best.Confidence))
+	}
+	for i := 1; i < len(candidates); i++ {
+		if candidates[i].Score < candidates[i-1].Score {
+			t.Fatalf("Expected sorted candidates, got %v", candidates)
+		}
+	}
+}
+
+func TestBreakRepeatingKeyErrors(t *testing.T) {
+	if _, err := BreakRepeatingKey([]byte("short"), Options{MinKeySize: 10, MaxKeySize: 5}); err == nil {
+		t.Fatalf("Expected an error for an empty key size range, got %v", err)
+	}
+	if _, err := BreakRepeatingKey([]byte("a"), Options{}); err == nil {
+		t.Fatalf("Expected an error for a too short ciphertext, got %v", err)
+	}
+}
+
+func TestBreakRepeatingKeyWithLanguageModel(t *testing.T) {
+	key := []byte("Lorem ipsum")
+	model := score.TrainNGram(3, []byte(testPlaintext))
+	candidates, err := BreakRepeatingKey(RepeatingKey([]byte(testPlaintext), key), Options{TopN: 5, Scorer: model})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if best := candidates[0]; !bytes.Equal(best.Key, key) || best.Score != model.Score(best.Plaintext) {
+		t.Fatalf("Expected %q scored by the model, got %q with %v", key, best.Key, best.Score)
+	}
+}
@@ -0,0 +1,35 @@
+package xor
+
+import (
+	"cryptopals/score"
+	"math"
+)
+
+type SingleByteResult struct {
+	Key       byte
+	Plaintext []byte
+	Score     float64
+	// Confidence is how much worse the runner-up key scored. Zero means a tie.
+	Confidence float64
+}
+
+// BreakSingleByte tries every key byte and keeps the one whose plaintext scores best.
+// On ties, the smallest key wins.
+func BreakSingleByte(ciphertext []byte, scorer score.Scorer) SingleByteResult {
+	best := SingleByteResult{Score: math.Inf(1)}
+	runnerUp := math.Inf(1)
+	for key := 0; key <= math.MaxUint8; key++ {
+		candidate := RepeatingKey(ciphertext, []byte{byte(key)})
+		readability := scorer.Score(candidate)
+		if readability < best.Score || best.Plaintext == nil {
+			runnerUp = best.Score
+			best = SingleByteResult{Key: byte(key), Plaintext: candidate, Score: readability}
+		} else if readability < runnerUp {
+			runnerUp = readability
+		}
+	}
+	if !(math.IsInf(runnerUp, 1) && math.IsInf(best.Score, 1)) {
+		best.Confidence = runnerUp - best.Score
+	}
+	return best
+}
@@ -0,0 +1,10 @@
+package xor
+
+// RepeatingKey XORs source with key repeated to its length.
+func RepeatingKey(source []byte, key []byte) []byte {
+	result := make([]byte, len(source))
+	for i := range result {
+		result[i] = source[i] ^ key[i%len(key)]
+	}
+	return result
+}